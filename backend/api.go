@@ -2,28 +2,47 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
 	"log"
+	"math"
 	"math/big"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 
+	"github.com/arbie-buckets/arbitrage"
 	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/cex"
+	"github.com/arbie-buckets/config"
+	"github.com/arbie-buckets/events"
+	"github.com/arbie-buckets/price"
+	"github.com/arbie-buckets/venue"
 )
 
 // SetupRoutes configures all API routes
-func SetupRoutes(r *gin.Engine, blockchainService *blockchain.BlockchainService) {
+func SetupRoutes(r *gin.Engine, blockchainService *blockchain.BlockchainService, venues *venue.Registry, feed *cex.Feed, prices *price.Oracle, history *price.History, scanner *arbitrage.Scanner, spreadScanner *arbitrage.SpreadScanner, profitCalculator *arbitrage.ProfitCalculator, slippageGuard *arbitrage.SlippageGuard, tracker *arbitrage.TradeTracker, hub *events.Hub, configManager *config.Manager) {
+	primaryChain := blockchain.ChainFor(configManager.Current().Chain.ChainID)
+
 	// Health check endpoint
 	r.GET("/ping", func(c *gin.Context) {
 		// Check blockchain connection health if service is available
 		var blockchainStatus string
 		if blockchainService != nil {
 			status := blockchainService.GetBlockchainStatus()
-			if status["connected"].(bool) {
+			state, _ := blockchainService.State()
+			switch {
+			case state == blockchain.StateInitializing:
+				blockchainStatus = "initializing"
+			case state == blockchain.StateReady && status["connected"].(bool):
 				blockchainStatus = "healthy"
-			} else {
+			default:
 				blockchainStatus = "unhealthy"
 			}
 		} else {
@@ -41,29 +60,62 @@ func SetupRoutes(r *gin.Engine, blockchainService *blockchain.BlockchainService)
 	api := r.Group("/api")
 	{
 		// Status endpoints
-		api.GET("/status", getBlockchainStatus(blockchainService))
+		api.GET("/status", getBlockchainStatus(blockchainService, primaryChain.Name))
 		api.GET("/ping", pingNetwork(blockchainService))
 
+		// The same status endpoints for each configured chain
+		api.GET("/chains", listChains)
+		chains := api.Group("/chains/:chainId")
+		chains.GET("/status", chainRoute(func(service *blockchain.BlockchainService) gin.HandlerFunc {
+			return getBlockchainStatus(service, service.Chain().Name)
+		}))
+		chains.GET("/ping", chainRoute(pingNetwork))
+
+		// Wallet and arbitrage endpoints trade on the primary chain: the
+		// tokens, venues and scanners are those of its network. Under
+		// /api/chains/:chainId they answer for the primary chain only.
+		walletBalance := getWalletBalance(blockchainService, prices, history)
+		opportunities := getArbitrageOpportunities(blockchainService, scanner, spreadScanner, profitCalculator)
+		settings := getArbitrageSettings(venues)
+		execute := executeArbitrageTrade(blockchainService, slippageGuard, tracker)
+
 		// Wallet endpoints
-		api.GET("/wallet/balance", getWalletBalance(blockchainService))
+		api.GET("/wallet/balance", walletBalance)
 		api.GET("/wallet/transactions", getTransactions)
+		chains.GET("/wallet/balance", primaryChainRoute(walletBalance))
 
 		// Arbitrage endpoints
-		api.GET("/arbitrage/opportunities", getArbitrageOpportunities(blockchainService))
-		api.GET("/arbitrage/settings", getArbitrageSettings)
+		api.GET("/arbitrage/opportunities", opportunities)
+		api.GET("/arbitrage/settings", settings)
 		api.PUT("/arbitrage/settings", updateArbitrageSettings)
-		api.POST("/arbitrage/execute", executeArbitrageTrade(blockchainService))
+		api.POST("/arbitrage/execute", execute)
+		chains.GET("/arbitrage/opportunities", primaryChainRoute(opportunities))
+		chains.GET("/arbitrage/settings", primaryChainRoute(settings))
+		chains.PUT("/arbitrage/settings", primaryChainRoute(updateArbitrageSettings))
+		chains.POST("/arbitrage/execute", primaryChainRoute(execute))
 		api.GET("/arbitrage/status", getTradingStatus)
 		api.PUT("/arbitrage/status", updateTradingStatus)
 
 		// Market data
-		api.GET("/markets/exchanges", getExchanges)
+		api.GET("/markets/exchanges", getExchanges(venues))
 		api.GET("/markets/tokens", getTokens)
+		api.GET("/markets/books", getOrderBooks(feed))
+		api.GET("/markets/prices/:token", getTokenPrice(prices))
+		api.GET("/markets/prices/:token/history", getPriceHistory(history))
+
+		// Server-Sent Events for heads, connection status, opportunities and
+		// trades
+		api.GET("/stream", streamEvents(hub))
+
+		// Admin endpoints
+		admin := api.Group("/admin", requireAdmin(configManager))
+		admin.POST("/reload", reloadConfig(configManager))
 	}
 }
 
-// Status handler
-func getBlockchainStatus(blockchainService *blockchain.BlockchainService) gin.HandlerFunc {
+// getBlockchainStatus reports the service's connection, naming network when
+// there is no service
+func getBlockchainStatus(blockchainService *blockchain.BlockchainService, network string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if blockchainService != nil {
 			// Get status directly from blockchain service
@@ -74,7 +126,7 @@ func getBlockchainStatus(blockchainService *blockchain.BlockchainService) gin.Ha
 			// Return disconnected status if service unavailable
 			c.JSON(http.StatusOK, gin.H{
 				"connected": false,
-				"network":   "Base Mainnet",
+				"network":   network,
 				"status":    "Disconnected",
 				"timestamp": time.Now().Format(time.RFC3339),
 			})
@@ -83,7 +135,7 @@ func getBlockchainStatus(blockchainService *blockchain.BlockchainService) gin.Ha
 }
 
 // Wallet handlers
-func getWalletBalance(blockchainService *blockchain.BlockchainService) gin.HandlerFunc {
+func getWalletBalance(blockchainService *blockchain.BlockchainService, prices *price.Oracle, history *price.History) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if blockchain service is initialized
 		if blockchainService == nil {
@@ -98,92 +150,52 @@ func getWalletBalance(blockchainService *blockchain.BlockchainService) gin.Handl
 			return
 		}
 
-		// Hardcoded token addresses for Base network
-		tokenAddresses := map[string]TokenInfo{
-			"eth": {
-				Address:  "0x4200000000000000000000000000000000000006",
-				Symbol:   "ETH",
-				Decimals: 18,
-			},
-			"usdc": {
-				Address:  "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
-				Symbol:   "USDC",
-				Decimals: 6,
-			},
-			"base": {
-				Address:  "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb",
-				Symbol:   "BASE",
-				Decimals: 18,
-			},
-		}
-		print(tokenAddresses) // For debugging purposes, to ensure token addresses are loaded correctly
-		// In a real implementation, you would fetch actual balances
-		// For now, using mock data to avoid blockchain calls for demonstration
-		// Uncomment the below code for actual blockchain integration:
-		/*
-			balances := []map[string]interface{}{}
-			var totalUsdValue float64 = 0
-
-			for id, token := range tokenAddresses {
-				tokenAddr := common.HexToAddress(token.Address)
-				balance, err := blockchainService.GetTokenBalance(tokenAddr)
-				if err != nil {
-					log.Printf("Failed to get balance for %s: %v", token.Symbol, err)
-					continue
-				}
+		// Value each token balance at the oracle price
+		balances := []map[string]interface{}{}
+		var totalUsdValue float64
+		for _, token := range blockchain.DefaultTokens {
+			balance, err := blockchainService.GetTokenBalance(c.Request.Context(), common.HexToAddress(token.Address))
+			if err != nil {
+				log.Printf("Failed to get balance for %s: %v", token.Symbol, err)
+				continue
+			}
 
-				// Convert to decimal value (simplified)
-				decimal := new(big.Float).SetInt(balance)
-				divisor := new(big.Float).SetFloat64(float64(10 ** token.Decimals))
-				decimal.Quo(decimal, divisor)
-
-				// Get USD value (would require price oracle in real implementation)
-				usdValue := 0.0 // Replace with actual price data
-				balanceFloat, _ := decimal.Float64()
-
-				balances = append(balances, map[string]interface{}{
-					"id":       id,
-					"name":     token.Symbol,
-					"symbol":   token.Symbol,
-					"balance":  decimal.String(),
-					"usdValue": usdValue,
-					"change":   "+0.0%", // Replace with actual data
-				})
+			amount := new(big.Float).Quo(
+				new(big.Float).SetInt(balance),
+				new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil)),
+			)
+
+			entry := map[string]interface{}{
+				"id":      strings.ToLower(token.Symbol),
+				"name":    token.Name,
+				"symbol":  token.Symbol,
+				"address": token.Address,
+				"balance": amount.Text('f', int(token.Decimals)),
+			}
+
+			aggregate, err := prices.Aggregate(c.Request.Context(), token)
+			if err != nil {
+				log.Printf("Failed to price %s: %v", token.Symbol, err)
+			} else {
+				amountFloat, _ := amount.Float64()
+				usdValue := amountFloat * aggregate.USD
+				entry["price"] = aggregate.USD
+				entry["priceConfidence"] = aggregate.Confidence
+				entry["usdValue"] = fmt.Sprintf("%.2f", usdValue)
 				totalUsdValue += usdValue
 			}
-		*/
-
-		// Mock wallet data for now
-		balances := []map[string]interface{}{
-			{
-				"id":       "eth",
-				"name":     "Ethereum",
-				"symbol":   "ETH",
-				"balance":  "3.245",
-				"usdValue": "7234.56",
-				"change":   "+2.3%",
-			},
-			{
-				"id":       "usdc",
-				"name":     "USD Coin",
-				"symbol":   "USDC",
-				"balance":  "2500.00",
-				"usdValue": "2500.00",
-				"change":   "0.0%",
-			},
-			{
-				"id":       "base",
-				"name":     "Base",
-				"symbol":   "BASE",
-				"balance":  "145.78",
-				"usdValue": "1850.45",
-				"change":   "-1.2%",
-			},
+
+			// 24h change is only reported once the history reaches back that far
+			if change, ok := history.Change(token, 24*time.Hour); ok {
+				entry["change"] = fmt.Sprintf("%+.1f%%", change)
+			}
+
+			balances = append(balances, entry)
 		}
 
 		c.JSON(http.StatusOK, gin.H{
 			"balances":  balances,
-			"total":     11585.01,
+			"total":     totalUsdValue,
 			"address":   walletAddress.Hex(),
 			"connected": true,
 		})
@@ -210,7 +222,7 @@ func getTransactions(c *gin.Context) {
 }
 
 // Arbitrage handlers
-func getArbitrageOpportunities(blockchainService *blockchain.BlockchainService) gin.HandlerFunc {
+func getArbitrageOpportunities(blockchainService *blockchain.BlockchainService, scanner *arbitrage.Scanner, spreadScanner *arbitrage.SpreadScanner, profitCalculator *arbitrage.ProfitCalculator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if blockchain service is initialized
 		if blockchainService == nil {
@@ -218,8 +230,7 @@ func getArbitrageOpportunities(blockchainService *blockchain.BlockchainService)
 			return
 		}
 
-		// Fetch opportunities from blockchain
-		opportunities, err := blockchainService.GetArbitrageOpportunities()
+		opportunities, err := findOpportunities(c.Request.Context(), blockchainService, scanner, spreadScanner, profitCalculator)
 		if err != nil {
 			log.Printf("Failed to get arbitrage opportunities: %v", err)
 			// Fallback to mock data if blockchain call fails
@@ -254,14 +265,7 @@ func getArbitrageOpportunities(blockchainService *blockchain.BlockchainService)
 		// Format opportunities for API response
 		formattedOpportunities := make([]map[string]interface{}, len(opportunities))
 		for i, opp := range opportunities {
-			formattedOpportunities[i] = map[string]interface{}{
-				"id":               "opp" + string(rune(i)),
-				"fromToken":        opp.FromToken,
-				"toToken":          opp.ToToken,
-				"potentialProfit":  opp.ProfitUSD,
-				"profitPercentage": opp.Percentage,
-				"timestamp":        time.Unix(opp.Timestamp, 0).Format(time.RFC3339),
-			}
+			formattedOpportunities[i] = formatOpportunity(i, opp)
 		}
 
 		c.JSON(http.StatusOK, gin.H{
@@ -270,17 +274,126 @@ func getArbitrageOpportunities(blockchainService *blockchain.BlockchainService)
 	}
 }
 
-func getArbitrageSettings(c *gin.Context) {
-	// Mock settings
-	settings := map[string]interface{}{
-		"gasThreshold":            15,
-		"minimumProfitPercentage": 0.5,
-		"tradingAmount":           1000,
-		"tradingInterval":         60,
-		"exchanges":               []string{"Uniswap", "Sushiswap", "Aerodrome", "Alienbase"},
+// findOpportunities combines the contract's opportunities with routes and
+// CEX–DEX spreads found across venues, each with its net profit calculated
+func findOpportunities(ctx context.Context, blockchainService *blockchain.BlockchainService, scanner *arbitrage.Scanner, spreadScanner *arbitrage.SpreadScanner, profitCalculator *arbitrage.ProfitCalculator) ([]blockchain.ArbitrageOpportunity, error) {
+	// Fetch opportunities from blockchain, sized like the routes below
+	opportunities, err := blockchainService.GetArbitrageOpportunities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opportunities = scanner.SizeOpportunities(ctx, opportunities)
+
+	// Add multi-hop and triangular routes found across all venues,
+	// sized against wallet, trade and pool limits
+	routes, err := scanner.FindOpportunities(ctx)
+	if err != nil {
+		log.Printf("Failed to search arbitrage routes: %v", err)
+	}
+	opportunities = append(opportunities, routes...)
+
+	// Add CEX–DEX spreads when the mode is enabled; these arrive with
+	// their net profit already calculated
+	if spreadScanner != nil {
+		spreads, err := spreadScanner.FindOpportunities(ctx)
+		if err != nil {
+			log.Printf("Failed to scan CEX-DEX spreads: %v", err)
+		}
+		opportunities = append(opportunities, spreads...)
+	}
+
+	// Account for L2 gas and the L1 data fee of executing each one, then
+	// rank them by what they would make at their sized amounts
+	profitCalculator.CalculateAll(ctx, opportunities)
+	arbitrage.SortByNetProfit(opportunities)
+
+	return opportunities, nil
+}
+
+// formatOpportunity converts an opportunity into its API representation
+func formatOpportunity(index int, opp blockchain.ArbitrageOpportunity) map[string]interface{} {
+	id := opp.ID
+	if id == "" {
+		id = fmt.Sprintf("opp%d", index+1)
+	}
+
+	formatted := map[string]interface{}{
+		"id":               id,
+		"fromToken":        opp.FromToken,
+		"toToken":          opp.ToToken,
+		"potentialProfit":  opp.ProfitUSD,
+		"profitPercentage": opp.Percentage,
+		"timestamp":        time.Unix(opp.Timestamp, 0).Format(time.RFC3339),
+	}
+	if opp.Type != "" {
+		formatted["type"] = opp.Type
+	}
+	if opp.AmountIn != nil && opp.AmountOut != nil {
+		formatted["amountIn"] = opp.AmountIn.String()
+		formatted["amountOut"] = opp.AmountOut.String()
+	}
+
+	if len(opp.Legs) > 0 {
+		legs := make([]map[string]interface{}, len(opp.Legs))
+		for i, leg := range opp.Legs {
+			legs[i] = map[string]interface{}{
+				"venue":     leg.Venue,
+				"tokenIn":   leg.TokenIn,
+				"tokenOut":  leg.TokenOut,
+				"amountIn":  leg.AmountIn.String(),
+				"amountOut": leg.AmountOut.String(),
+			}
+		}
+		formatted["legs"] = legs
+	}
+
+	if opp.SizedBy != "" {
+		formatted["sizedBy"] = opp.SizedBy
+		formatted["priceImpact"] = opp.PriceImpact
+	}
+
+	if opp.Type == blockchain.OpportunityCEXDEX {
+		formatted["spreadBps"] = opp.SpreadBps
+	}
+
+	if opp.Profit != nil {
+		formatted["profit"] = map[string]interface{}{
+			"quoteToken":     opp.Profit.QuoteToken,
+			"grossProfit":    opp.Profit.GrossProfit.String(),
+			"fees":           opp.Profit.Fees.String(),
+			"netProfit":      opp.Profit.NetProfit.String(),
+			"grossProfitUsd": opp.Profit.GrossProfitUSD,
+			"feesUsd":        opp.Profit.FeesUSD,
+			"netProfitUsd":   opp.Profit.NetProfitUSD,
+			"l2GasLimit":     opp.Profit.Cost.L2GasLimit,
+			"l2GasPriceWei":  opp.Profit.Cost.L2GasPrice.String(),
+			"l2FeeWei":       opp.Profit.Cost.L2Fee.String(),
+			"l1FeeWei":       opp.Profit.Cost.L1Fee.String(),
+		}
 	}
 
-	c.JSON(http.StatusOK, settings)
+	return formatted
+}
+
+func getArbitrageSettings(venues *venue.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Exchanges come from the venue registry
+		exchanges := []string{}
+		for _, v := range venues.List() {
+			exchanges = append(exchanges, v.Name())
+		}
+
+		// Mock settings
+		settings := map[string]interface{}{
+			"gasThreshold":            15,
+			"minimumProfitPercentage": 0.5,
+			"tradingAmount":           1000,
+			"tradingInterval":         60,
+			"exchanges":               exchanges,
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
 }
 
 func updateArbitrageSettings(c *gin.Context) {
@@ -295,13 +408,25 @@ func updateArbitrageSettings(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
-func executeArbitrageTrade(blockchainService *blockchain.BlockchainService) gin.HandlerFunc {
+func executeArbitrageTrade(blockchainService *blockchain.BlockchainService, slippageGuard *arbitrage.SlippageGuard, tracker *arbitrage.TradeTracker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if blockchain service is initialized
 		if blockchainService == nil {
 			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Blockchain service not available"})
 			return
 		}
+		if err := blockchainService.Ready(); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Shutdown waits for the send to finish, and refuses new ones
+		done, err := tracker.BeginSend()
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		defer done()
 
 		var trade map[string]interface{}
 		if err := c.ShouldBindJSON(&trade); err != nil {
@@ -323,47 +448,121 @@ func executeArbitrageTrade(blockchainService *blockchain.BlockchainService) gin.
 		}
 
 		amountFloat, ok := trade["amount"].(float64)
-		if !ok {
+		if !ok || amountFloat <= 0 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid amount"})
 			return
 		}
 
-		minReturnFloat, ok := trade["minReturn"].(float64)
-		if !ok {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minReturn"})
+		// minReturn and slippageBps are optional overrides
+		var toleranceBps *uint32
+		if value, exists := trade["slippageBps"]; exists {
+			bps, ok := value.(float64)
+			if !ok || bps < 0 || bps >= arbitrage.MaxToleranceBps || bps != math.Trunc(bps) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slippageBps"})
+				return
+			}
+			converted := uint32(bps)
+			toleranceBps = &converted
+		}
+
+		fromToken, err := lookupToken(c.Request.Context(), blockchainService, fromTokenStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid fromToken: %v", err)})
+			return
+		}
+		toToken, err := lookupToken(c.Request.Context(), blockchainService, toTokenStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid toToken: %v", err)})
 			return
 		}
 
-		// Convert addresses to Ethereum addresses
-		fromToken := common.HexToAddress(fromTokenStr)
-		toToken := common.HexToAddress(toTokenStr)
+		var minReturnOverride *big.Int
+		if value, exists := trade["minReturn"]; exists {
+			minReturnFloat, ok := value.(float64)
+			if !ok || minReturnFloat < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minReturn"})
+				return
+			}
+			minReturnOverride = arbitrage.FromUnits(minReturnFloat, toToken.Decimals)
+		}
 
-		// Convert amounts to big integers (for simplicity, assuming 18 decimals)
-		amount := new(big.Int).Mul(
-			big.NewInt(int64(amountFloat)),
-			new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
-		)
-		minReturn := new(big.Int).Mul(
-			big.NewInt(int64(minReturnFloat)),
-			new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
-		)
+		amount := arbitrage.FromUnits(amountFloat, fromToken.Decimals)
 
-		// Execute the arbitrage trade
-		txHash, err := blockchainService.ExecuteArbitrage(fromToken, toToken, amount, minReturn)
+		// Quote the trade now and derive minReturn from the tolerance
+		guarded, err := slippageGuard.Prepare(c.Request.Context(), venue.Pair{Base: fromToken, Quote: toToken}, amount, toleranceBps, minReturnOverride)
+		if err != nil {
+			log.Printf("Failed to quote arbitrage trade: %v", err)
+			if errors.Is(err, arbitrage.ErrNoQuote) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Execute the arbitrage trade, refusing to send it once signed if the
+		// chain has moved past the quote. The trade is accepted now, so a
+		// client disconnecting must not abandon a half-sent transaction; the
+		// send stays bounded by the send timeout ExecuteArbitrage applies.
+		checkFresh := func(ctx context.Context) error {
+			return slippageGuard.CheckFresh(ctx, guarded.Quote)
+		}
+		sendCtx := context.WithoutCancel(c.Request.Context())
+		txHash, err := blockchainService.ExecuteArbitrage(sendCtx, fromToken.HexAddress(), toToken.HexAddress(), amount, guarded.MinReturn, checkFresh)
+		if errors.Is(err, arbitrage.ErrStaleQuote) {
+			log.Printf("Refusing arbitrage trade: %v", err)
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		if err != nil {
 			log.Printf("Failed to execute arbitrage trade: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to execute trade"})
 			return
 		}
 
+		// Report the trade on the event stream until it is mined
+		tracker.Track(arbitrage.PendingTrade{
+			TxHash:    txHash,
+			FromToken: fromToken.Address,
+			ToToken:   toToken.Address,
+			AmountIn:  amount.String(),
+			MinReturn: guarded.MinReturn.String(),
+		})
+
 		c.JSON(http.StatusOK, gin.H{
-			"success":       true,
-			"transactionId": txHash,
-			"timestamp":     time.Now().Format(time.RFC3339),
+			"success":              true,
+			"transactionId":        txHash,
+			"amountIn":             amount.String(),
+			"quotedAmount":         guarded.Quote.AmountOut.String(),
+			"quoteVenue":           guarded.Quote.VenueID,
+			"quoteBlock":           guarded.Quote.BlockNumber,
+			"slippageToleranceBps": guarded.ToleranceBps,
+			"minReturn":            guarded.MinReturn.String(),
+			"minReturnOverridden":  guarded.Overridden,
+			"timestamp":            time.Now().Format(time.RFC3339),
 		})
 	}
 }
 
+// lookupToken resolves a token symbol or address to its metadata. Tokens
+// that are not tracked must be addresses of contracts reporting ERC20
+// decimals.
+func lookupToken(ctx context.Context, blockchainService *blockchain.BlockchainService, symbolOrAddress string) (blockchain.TokenInfo, error) {
+	if token, ok := blockchain.FindToken(symbolOrAddress); ok {
+		return token, nil
+	}
+	if !common.IsHexAddress(symbolOrAddress) {
+		return blockchain.TokenInfo{}, fmt.Errorf("unknown token %q", symbolOrAddress)
+	}
+
+	address := common.HexToAddress(symbolOrAddress)
+	decimals, err := blockchainService.GetTokenDecimals(ctx, address)
+	if err != nil {
+		return blockchain.TokenInfo{}, err
+	}
+	return blockchain.TokenInfo{Address: address.Hex(), Symbol: address.Hex(), Decimals: decimals}, nil
+}
+
 func getTradingStatus(c *gin.Context) {
 	// Mock active status
 	c.JSON(http.StatusOK, gin.H{
@@ -394,28 +593,268 @@ func updateTradingStatus(c *gin.Context) {
 }
 
 // Market data handlers
-func getExchanges(c *gin.Context) {
-	exchanges := []map[string]interface{}{
-		{"id": "uniswap", "name": "Uniswap"},
-		{"id": "sushiswap", "name": "Sushiswap"},
-		{"id": "aerodrome", "name": "Aerodrome"},
-		{"id": "alienbase", "name": "Alienbase"},
+func getExchanges(venues *venue.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Probe every registered venue for health and latency
+		health := venues.Health(c.Request.Context())
+
+		exchanges := make([]map[string]interface{}, len(health))
+		for i, h := range health {
+			exchanges[i] = map[string]interface{}{
+				"id":         h.ID,
+				"name":       h.Name,
+				"kind":       h.Kind,
+				"healthy":    h.Healthy,
+				"latency_ms": h.Latency.Milliseconds(),
+				"checked_at": h.CheckedAt.Format(time.RFC3339),
+			}
+			if h.Error != "" {
+				exchanges[i]["error"] = h.Error
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"exchanges": exchanges})
+	}
+}
+
+func getOrderBooks(feed *cex.Feed) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		books := []map[string]interface{}{}
+		for _, stream := range feed.Streams() {
+			// Consumers should ignore books flagged as stale
+			for _, top := range stream.TopOfBooks() {
+				books = append(books, map[string]interface{}{
+					"exchange":   top.Exchange.String(),
+					"market":     top.Market.String(),
+					"bid":        top.Bid,
+					"bid_size":   top.BidSize,
+					"ask":        top.Ask,
+					"ask_size":   top.AskSize,
+					"sequence":   top.Sequence,
+					"synced":     top.Synced,
+					"age_ms":     top.Age().Milliseconds(),
+					"stale":      top.Stale(stream.MaxBookAge()),
+					"updated_at": top.UpdatedAt.Format(time.RFC3339Nano),
+				})
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"streaming": len(feed.Streams()) > 0,
+			"books":     books,
+		})
+	}
+}
+
+func getTokenPrice(prices *price.Oracle) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := blockchain.FindToken(c.Param("token"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown token"})
+			return
+		}
+
+		aggregate, err := prices.Aggregate(c.Request.Context(), token)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+
+		sources := make([]map[string]interface{}, len(aggregate.Sources))
+		for i, source := range aggregate.Sources {
+			sources[i] = map[string]interface{}{
+				"source": source.Source,
+				"used":   source.Used,
+			}
+			if source.Error == "" {
+				sources[i]["price"] = source.USD
+				sources[i]["timestamp"] = source.Timestamp.Format(time.RFC3339)
+			}
+			if source.Rejected != "" {
+				sources[i]["rejected"] = source.Rejected
+			}
+			if source.Error != "" {
+				sources[i]["error"] = source.Error
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"token":      token.Symbol,
+			"address":    token.Address,
+			"price":      aggregate.USD,
+			"confidence": aggregate.Confidence,
+			"deviation":  aggregate.Deviation,
+			"sources":    sources,
+			"timestamp":  aggregate.Timestamp.Format(time.RFC3339),
+		})
+	}
+}
+
+func getPriceHistory(history *price.History) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := blockchain.FindToken(c.Param("token"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown token"})
+			return
+		}
+
+		interval, err := price.ParseInterval(c.DefaultQuery("interval", "1h"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Optionally limit the response to the most recent candles
+		candles := history.Candles(token, interval, time.Time{})
+		if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit < len(candles) {
+			candles = candles[len(candles)-limit:]
+		}
+
+		formatted := make([]map[string]interface{}, len(candles))
+		for i, candle := range candles {
+			formatted[i] = map[string]interface{}{
+				"time":  candle.Start.Format(time.RFC3339),
+				"open":  candle.Open,
+				"high":  candle.High,
+				"low":   candle.Low,
+				"close": candle.Close,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"token":    token.Symbol,
+			"interval": interval,
+			"candles":  formatted,
+		})
+	}
+}
+
+// requireAdmin accepts requests bearing the configured admin token, or
+// from localhost when no token is configured
+func requireAdmin(configManager *config.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := string(configManager.Current().Server.AdminToken)
+		if token == "" {
+			// The peer address, not forwarding headers a client could set
+			if ip := net.ParseIP(c.RemoteIP()); ip == nil || !ip.IsLoopback() {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin endpoints are only available from localhost without an admin token"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+			return
+		}
+		c.Next()
 	}
+}
+
+// reloadConfig reloads the config file and applies it. Rejected changes
+// are returned with the error so the caller can see what was refused.
+func reloadConfig(configManager *config.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := configManager.Reload("api " + c.ClientIP())
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, result)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// streamEvents serves the event hub as Server-Sent Events. Clients choose
+// topics with ?topics=blocks,connection and resume with Last-Event-ID.
+func streamEvents(hub *events.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var topics []string
+		if value := c.Query("topics"); value != "" {
+			for _, topic := range strings.Split(value, ",") {
+				topic = strings.TrimSpace(topic)
+				if !events.ValidTopic(topic) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown topic %q", topic), "topics": events.Topics})
+					return
+				}
+				topics = append(topics, topic)
+			}
+		}
+
+		// EventSource sends the header on reconnect; the query parameter lets
+		// a client resume on its first connection
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("lastEventId")
+		}
+
+		sub, replay, complete := hub.Subscribe(topics, events.ParseLastEventID(lastEventID), 64)
+		defer sub.Close()
 
-	c.JSON(http.StatusOK, gin.H{"exchanges": exchanges})
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		w := c.Writer
+		if err := events.WriteRetry(w, events.DefaultRetry); err != nil {
+			return
+		}
+		if !complete {
+			_ = events.WriteSSE(w, events.Event{Type: events.TypeReset, Time: time.Now()})
+		}
+		for _, event := range replay {
+			if err := events.WriteSSE(w, event); err != nil {
+				return
+			}
+		}
+		w.Flush()
+
+		heartbeat := time.NewTicker(events.DefaultHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			var err error
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, ok := <-sub.Events():
+				if !ok {
+					// Dropped for falling behind; the client resumes from
+					// its last event ID
+					return
+				}
+				err = events.WriteSSE(w, event)
+			case <-heartbeat.C:
+				err = events.WriteSSE(w, events.Event{Type: events.TypeHeartbeat, Time: time.Now()})
+			}
+			if err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
 }
 
 func getTokens(c *gin.Context) {
-	tokens := []map[string]interface{}{
-		{"id": "eth", "name": "Ethereum", "symbol": "ETH", "decimals": 18, "address": "0x4200000000000000000000000000000000000006"},
-		{"id": "usdc", "name": "USD Coin", "symbol": "USDC", "decimals": 6, "address": "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"},
-		{"id": "base", "name": "Base", "symbol": "BASE", "decimals": 18, "address": "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb"},
+	// Tokens come from the blockchain package's token list
+	tokens := make([]map[string]interface{}, len(blockchain.DefaultTokens))
+	for i, token := range blockchain.DefaultTokens {
+		tokens[i] = map[string]interface{}{
+			"id":       strings.ToLower(token.Symbol),
+			"name":     token.Name,
+			"symbol":   token.Symbol,
+			"decimals": token.Decimals,
+			"address":  token.Address,
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
 }
 
-// pingNetwork tests connectivity to the Base blockchain network
+// pingNetwork tests connectivity to the service's chain
 func pingNetwork(blockchainService *blockchain.BlockchainService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		startTime := time.Now()
@@ -430,24 +869,11 @@ func pingNetwork(blockchainService *blockchain.BlockchainService) gin.HandlerFun
 			return
 		}
 
-		// Get the connection client to test connectivity
-		client, err := blockchainService.GetConnectionClient()
-		if err != nil {
-			c.JSON(http.StatusOK, gin.H{
-				"connected":  false,
-				"error":      err.Error(),
-				"latency_ms": time.Since(startTime).Milliseconds(),
-				"latency":    time.Since(startTime).String(),
-				"timestamp":  time.Now().Format(time.RFC3339),
-			})
-			return
-		}
-
 		// Query the latest block number as a ping test
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		blockNumber, err := client.BlockNumber(ctx)
+		blockNumber, err := blockchainService.GetBlockNumber(ctx)
 
 		// Calculate response time
 		latency := time.Since(startTime)
@@ -464,21 +890,107 @@ func pingNetwork(blockchainService *blockchain.BlockchainService) gin.HandlerFun
 		}
 
 		// Get additional network information
-		gasPrice, err := client.SuggestGasPrice(ctx)
+		gasPrice, err := blockchainService.SuggestGasPrice(ctx)
 		if err != nil {
 			gasPrice = big.NewInt(0)
 		}
 
 		// Return comprehensive network status
-		c.JSON(http.StatusOK, gin.H{
+		response := gin.H{
 			"connected":      true,
 			"latency_ms":     latency.Milliseconds(),
 			"latency":        latency.String(),
 			"block_number":   blockNumber,
-			"chain_id":       blockchainService.GetChainID().String(),
 			"gas_price_wei":  gasPrice.String(),
 			"gas_price_gwei": float64(gasPrice.Int64()) / 1000000000,
+			"network":        blockchainService.Chain().Name,
 			"timestamp":      time.Now().Format(time.RFC3339),
-		})
+		}
+		if chainID := blockchainService.GetChainID(); chainID != nil {
+			response["chain_id"] = chainID.String()
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// listChains returns the chain registry, marking the configured chains with
+// their service state. Configured chains missing from the registry are
+// included too.
+func listChains(c *gin.Context) {
+	configured := make(map[uint64]*blockchain.BlockchainService)
+	for _, service := range blockchain.GetServices() {
+		configured[service.Chain().ChainID] = service
+	}
+	primary := blockchain.GetService()
+
+	chains := blockchain.Chains()
+	for chainID, service := range configured {
+		if _, ok := blockchain.LookupChain(chainID); !ok {
+			chains = append(chains, service.Chain())
+		}
+	}
+
+	result := make([]gin.H, len(chains))
+	for i, chain := range chains {
+		entry := gin.H{
+			"chainId":        chain.ChainID,
+			"name":           chain.Name,
+			"nativeCurrency": chain.NativeCurrency,
+			"explorerUrl":    chain.ExplorerURL,
+			"contracts":      chain.Contracts,
+			"testnet":        chain.Testnet,
+			"configured":     false,
+		}
+		if service, ok := configured[chain.ChainID]; ok {
+			state, _ := service.State()
+			status, _ := service.ConnectionStatus()
+			entry["configured"] = true
+			entry["primary"] = service == primary
+			entry["state"] = state.String()
+			entry["status"] = status.String()
+		}
+		result[i] = entry
 	}
+
+	c.JSON(http.StatusOK, gin.H{"chains": result})
+}
+
+// chainRoute serves a handler built for each configured chain, selected by
+// the :chainId path parameter
+func chainRoute(build func(*blockchain.BlockchainService) gin.HandlerFunc) gin.HandlerFunc {
+	handlers := make(map[uint64]gin.HandlerFunc)
+	for _, service := range blockchain.GetServices() {
+		handlers[service.Chain().ChainID] = build(service)
+	}
+
+	return func(c *gin.Context) {
+		chainID, err := strconv.ParseUint(c.Param("chainId"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chain ID"})
+			return
+		}
+		handler, ok := handlers[chainID]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Chain %d is not configured", chainID)})
+			return
+		}
+		handler(c)
+	}
+}
+
+// primaryChainRoute serves handler under the :chainId path parameter for
+// the primary chain, and refuses the other configured chains, which are
+// monitored but not traded on
+func primaryChainRoute(handler gin.HandlerFunc) gin.HandlerFunc {
+	primary := blockchain.GetService()
+	return chainRoute(func(service *blockchain.BlockchainService) gin.HandlerFunc {
+		if service == primary {
+			return handler
+		}
+		return func(c *gin.Context) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Trading is only available on the primary chain, %s (%d)", primary.Chain().Name, primary.Chain().ChainID),
+			})
+		}
+	})
 }
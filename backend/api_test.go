@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gin-gonic/gin"
+
+	"github.com/arbie-buckets/arbitrage"
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/blockchain/simulated"
+	"github.com/arbie-buckets/blockchain/simulated/simtest"
+	"github.com/arbie-buckets/events"
+	"github.com/arbie-buckets/price"
+	"github.com/arbie-buckets/price/pricetest"
+	"github.com/arbie-buckets/venue"
+)
+
+var (
+	testRouter = common.HexToAddress("0x00000000000000000000000000000000000000F1")
+
+	// testUSDCBalance is what the stub USDC contract reports for any holder
+	testUSDCBalance = big.NewInt(250_000_000)
+)
+
+// newTestChain starts a simulated chain running the real Arbitrage
+// contract, with a USDC stub whose balanceOf is testUSDCBalance
+func newTestChain(t *testing.T) *simtest.Chain {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	usdc, _ := blockchain.FindToken("USDC")
+	return simtest.New(t, simtest.Options{
+		Routers: []common.Address{testRouter},
+		Alloc: types.GenesisAlloc{
+			usdc.HexAddress(): {Code: returnWord(testUSDCBalance), Balance: new(big.Int)},
+		},
+	})
+}
+
+// returnWord is runtime code returning value as a 32 byte word to any call
+func returnWord(value *big.Int) []byte {
+	code := append([]byte{0x7f}, common.LeftPadBytes(value.Bytes(), 32)...) // PUSH32 value
+	return append(code, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3)     // MSTORE at 0, RETURN 32 bytes
+}
+
+// rateVenue quotes every pair at a fixed rate of whole quote tokens per
+// whole base token
+type rateVenue struct {
+	rate float64
+}
+
+func (v *rateVenue) ID() string                        { return "fixed-rate" }
+func (v *rateVenue) Name() string                      { return "Fixed Rate" }
+func (v *rateVenue) Kind() venue.Kind                  { return venue.KindDEX }
+func (v *rateVenue) SupportsPair(pair venue.Pair) bool { return true }
+
+func (v *rateVenue) Quote(ctx context.Context, pair venue.Pair, amountIn *big.Int) (*venue.Quote, error) {
+	out := new(big.Float).SetInt(amountIn)
+	out.Mul(out, big.NewFloat(v.rate))
+	out.Mul(out, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(pair.Quote.Decimals)), nil)))
+	out.Quo(out, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(pair.Base.Decimals)), nil)))
+	amountOut, _ := out.Int(nil)
+	return &venue.Quote{VenueID: v.ID(), Pair: pair, AmountIn: amountIn, AmountOut: amountOut, Timestamp: time.Now()}, nil
+}
+
+func (v *rateVenue) Liquidity(ctx context.Context, pair venue.Pair) (*venue.Liquidity, error) {
+	return nil, venue.ErrUnsupportedPair
+}
+
+// serve runs a single request against handler and decodes the JSON body
+func serve(t *testing.T, method, path string, body interface{}, handler gin.HandlerFunc) (int, map[string]interface{}) {
+	t.Helper()
+	r := gin.New()
+	r.Handle(method, path, handler)
+
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+	}
+	return w.Code, response
+}
+
+func TestGetArbitrageSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := venue.NewRegistry()
+	if err := registry.Register(&rateVenue{rate: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	code, response := serve(t, http.MethodGet, "/settings", nil, getArbitrageSettings(registry))
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, body %v", code, response)
+	}
+	exchanges, _ := response["exchanges"].([]interface{})
+	if len(exchanges) != 1 || exchanges[0] != "Fixed Rate" {
+		t.Errorf("exchanges = %v, want [Fixed Rate]", response["exchanges"])
+	}
+}
+
+func TestGetWalletBalance(t *testing.T) {
+	chain := newTestChain(t)
+	prices := pricetest.Fixed{"ETH": 2000, "USDC": 1, "BASE": 0.5}
+	oracle := price.NewOracle(price.OracleConfig{}, prices)
+	history, err := price.NewHistory(prices, blockchain.DefaultTokens, price.HistoryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, response := serve(t, http.MethodGet, "/balance", nil, getWalletBalance(chain.Service, oracle, history))
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, body %v", code, response)
+	}
+	if response["address"] != chain.Wallet.Hex() {
+		t.Errorf("address = %v, want %s", response["address"], chain.Wallet.Hex())
+	}
+
+	balances, _ := response["balances"].([]interface{})
+	var usdc map[string]interface{}
+	for _, entry := range balances {
+		if entry := entry.(map[string]interface{}); entry["symbol"] == "USDC" {
+			usdc = entry
+		}
+	}
+	if usdc == nil {
+		t.Fatalf("no USDC balance in %v", balances)
+	}
+	if usdc["balance"] != "250.000000" {
+		t.Errorf("USDC balance = %v, want 250.000000", usdc["balance"])
+	}
+	if usdc["usdValue"] != "250.00" {
+		t.Errorf("USDC usdValue = %v, want 250.00", usdc["usdValue"])
+	}
+	if total, _ := response["total"].(float64); total < 250 {
+		t.Errorf("total = %v, want at least the USDC value", response["total"])
+	}
+}
+
+func TestExecuteArbitrageTrade(t *testing.T) {
+	chain := newTestChain(t)
+	handler := newExecuteHandler(t, chain)
+
+	eth, _ := blockchain.FindToken("ETH")
+	usdc, _ := blockchain.FindToken("USDC")
+	request := map[string]interface{}{
+		"fromToken": eth.Address,
+		"toToken":   usdc.Address,
+		"amount":    1.0,
+	}
+	code, response := serve(t, http.MethodPost, "/execute", request, handler)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, body %v", code, response)
+	}
+	if response["quotedAmount"] != "2000000000" {
+		t.Errorf("quotedAmount = %v, want 2000000000", response["quotedAmount"])
+	}
+	if response["minReturn"] != "1990000000" {
+		t.Errorf("minReturn = %v, want the quote less the default 50 bps", response["minReturn"])
+	}
+
+	// Mine the trade
+	chain.Backend.Commit()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	receipt, err := chain.Service.WaitForTransaction(ctx, common.HexToHash(response["transactionId"].(string)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("trade reverted: %+v", receipt)
+	}
+
+}
+
+// newExecuteHandler returns the execute handler quoting ETH at 2000 USDC
+func newExecuteHandler(t *testing.T, chain *simtest.Chain) gin.HandlerFunc {
+	t.Helper()
+	registry := venue.NewRegistry()
+	if err := registry.Register(&rateVenue{rate: 2000}); err != nil {
+		t.Fatal(err)
+	}
+	guard := arbitrage.NewSlippageGuard(registry, chain.Service, arbitrage.DefaultSlippageConfig())
+	tracker, err := arbitrage.NewTradeTracker(chain.Service, events.NewHub(16), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tracker.Shutdown(context.Background()) })
+	return executeArbitrageTrade(chain.Service, guard, tracker)
+}
+
+func TestExecuteArbitrageTradeRefusesStaleQuote(t *testing.T) {
+	chain := newTestChain(t)
+	handler := newExecuteHandler(t, chain)
+
+	// The chain moves past the quote while the trade is being signed
+	chain.Signer.OnSign = func() {
+		for i := uint64(0); i <= arbitrage.DefaultMaxQuoteAgeBlocks; i++ {
+			chain.Backend.Commit()
+		}
+	}
+
+	request := map[string]interface{}{"fromToken": "ETH", "toToken": "USDC", "amount": 1.0}
+	code, response := serve(t, http.MethodPost, "/execute", request, handler)
+	if code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d; body %v", code, http.StatusConflict, response)
+	}
+
+	// Nothing was sent
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chain.Backend.Commit()
+	client, err := chain.Backend.Dial(ctx, simulated.RPCURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, err := client.PendingNonceAt(ctx, chain.Wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 0 {
+		t.Errorf("stale trade sent: wallet nonce = %d, want 0", nonce)
+	}
+}
+
+func TestExecuteArbitrageTradeRejectsInvalidRequests(t *testing.T) {
+	chain := newTestChain(t)
+	handler := newExecuteHandler(t, chain)
+
+	tests := []struct {
+		name    string
+		request map[string]interface{}
+	}{
+		{"slippage above 100%", map[string]interface{}{"fromToken": "ETH", "toToken": "USDC", "amount": 1.0, "slippageBps": 10001.0}},
+		{"slippage overflowing uint32", map[string]interface{}{"fromToken": "ETH", "toToken": "USDC", "amount": 1.0, "slippageBps": 4294967346.0}},
+		{"negative slippage", map[string]interface{}{"fromToken": "ETH", "toToken": "USDC", "amount": 1.0, "slippageBps": -1.0}},
+		{"slippage of 100%", map[string]interface{}{"fromToken": "ETH", "toToken": "USDC", "amount": 1.0, "slippageBps": 10000.0}},
+		{"fractional slippage", map[string]interface{}{"fromToken": "ETH", "toToken": "USDC", "amount": 1.0, "slippageBps": 12.7}},
+		{"unknown symbol", map[string]interface{}{"fromToken": "NOPE", "toToken": "USDC", "amount": 1.0}},
+		{"address without a token", map[string]interface{}{"fromToken": "ETH", "toToken": testRouter.Hex(), "amount": 1.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := serve(t, http.MethodPost, "/execute", tt.request, handler)
+			if code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body %v", code, http.StatusBadRequest, response)
+			}
+		})
+	}
+}
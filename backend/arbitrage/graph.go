@@ -0,0 +1,190 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/venue"
+)
+
+// Edge is a directed swap from one token to another on a single venue
+type Edge struct {
+	Venue venue.Venue
+	Pair  venue.Pair
+
+	// liquidity is the snapshot taken when the graph was built; nil when the
+	// venue could not report depth
+	liquidity *venue.Liquidity
+}
+
+// From returns the key of the token the edge sells
+func (e *Edge) From() string { return tokenKey(e.Pair.Base) }
+
+// To returns the key of the token the edge buys
+func (e *Edge) To() string { return tokenKey(e.Pair.Quote) }
+
+// Liquidity returns the snapshot taken when the graph was built
+func (e *Edge) Liquidity() *venue.Liquidity { return e.liquidity }
+
+// AmountOut prices a swap along the edge, simulating against the snapshot
+// when the venue supports it and falling back to a live quote otherwise
+func (e *Edge) AmountOut(ctx context.Context, amountIn *big.Int) (*big.Int, error) {
+	if sim, ok := e.Venue.(venue.Simulator); ok && e.liquidity != nil {
+		if out := sim.SimulateQuote(e.liquidity, amountIn); out != nil {
+			return out, nil
+		}
+	}
+
+	quote, err := e.Venue.Quote(ctx, e.Pair, amountIn)
+	if err != nil {
+		return nil, err
+	}
+	return quote.AmountOut, nil
+}
+
+// Graph is a directed token graph whose edges are the pools of every venue
+type Graph struct {
+	tokens map[string]blockchain.TokenInfo
+	edges  map[string][]*Edge
+}
+
+// BuildGraph snapshots the liquidity of every pair on every registered venue
+// and links the tokens through the pools that exist
+func BuildGraph(ctx context.Context, registry *venue.Registry, tokens []blockchain.TokenInfo) *Graph {
+	graph := &Graph{
+		tokens: make(map[string]blockchain.TokenInfo),
+		edges:  make(map[string][]*Edge),
+	}
+	for _, token := range tokens {
+		graph.tokens[tokenKey(token)] = token
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+	// Only on-chain venues can be legs of an executable route
+	for _, v := range registry.ByKind(venue.KindDEX) {
+		for i := range tokens {
+			for j := i + 1; j < len(tokens); j++ {
+				pair := venue.Pair{Base: tokens[i], Quote: tokens[j]}
+				if !v.SupportsPair(pair) {
+					continue
+				}
+
+				wg.Add(1)
+				go func(v venue.Venue, pair venue.Pair) {
+					defer wg.Done()
+
+					liquidity, err := v.Liquidity(ctx, pair)
+					if err != nil {
+						if !errors.Is(err, venue.ErrUnsupportedPair) {
+							log.Printf("Failed to load %s liquidity on %s: %v", pair, v.ID(), err)
+						}
+						return
+					}
+					if liquidity.BaseReserve.Sign() <= 0 || liquidity.QuoteReserve.Sign() <= 0 {
+						return
+					}
+
+					mutex.Lock()
+					defer mutex.Unlock()
+					graph.addEdge(&Edge{Venue: v, Pair: pair, liquidity: liquidity})
+					graph.addEdge(&Edge{Venue: v, Pair: pair.Reverse(), liquidity: liquidity.Reverse()})
+				}(v, pair)
+			}
+		}
+	}
+	wg.Wait()
+
+	return graph
+}
+
+// addEdge links the edge under the token it sells
+func (g *Graph) addEdge(edge *Edge) {
+	g.edges[edge.From()] = append(g.edges[edge.From()], edge)
+}
+
+// Token returns the token registered under key
+func (g *Graph) Token(key string) (blockchain.TokenInfo, bool) {
+	token, ok := g.tokens[key]
+	return token, ok
+}
+
+// Edges returns the edges that sell the given token
+func (g *Graph) Edges(token blockchain.TokenInfo) []*Edge {
+	return g.edges[tokenKey(token)]
+}
+
+// Cycles returns every simple cycle through start with between two and
+// maxHops legs. Intermediate tokens are visited at most once and no pool is
+// traded twice within a cycle.
+func (g *Graph) Cycles(start blockchain.TokenInfo, maxHops int) [][]*Edge {
+	var (
+		cycles  [][]*Edge
+		path    []*Edge
+		visited = map[string]bool{}
+		startID = tokenKey(start)
+	)
+
+	var walk func(current string)
+	walk = func(current string) {
+		for _, edge := range g.edges[current] {
+			if usesPool(path, edge) {
+				continue
+			}
+
+			if edge.To() == startID {
+				if len(path) >= 1 {
+					cycle := make([]*Edge, len(path)+1)
+					copy(cycle, path)
+					cycle[len(path)] = edge
+					cycles = append(cycles, cycle)
+				}
+				continue
+			}
+
+			if len(path)+1 >= maxHops || visited[edge.To()] {
+				continue
+			}
+
+			visited[edge.To()] = true
+			path = append(path, edge)
+			walk(edge.To())
+			path = path[:len(path)-1]
+			visited[edge.To()] = false
+		}
+	}
+	walk(startID)
+
+	return cycles
+}
+
+// usesPool reports whether the path already trades through the edge's pool
+func usesPool(path []*Edge, edge *Edge) bool {
+	for _, e := range path {
+		if e.Venue.ID() == edge.Venue.ID() && pairKey(e.Pair) == pairKey(edge.Pair) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenKey identifies a token by its lower-cased address
+func tokenKey(token blockchain.TokenInfo) string {
+	return strings.ToLower(token.Address)
+}
+
+// pairKey identifies an unordered pair of tokens
+func pairKey(pair venue.Pair) string {
+	a, b := tokenKey(pair.Base), tokenKey(pair.Quote)
+	if a > b {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
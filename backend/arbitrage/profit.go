@@ -0,0 +1,136 @@
+package arbitrage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/price"
+)
+
+// nativeDecimals is the number of decimals of the chain's gas token
+const nativeDecimals = 18
+
+// ProfitCalculator turns gross opportunities into net results by pricing the
+// L2 execution gas and L1 data fee of executing them
+type ProfitCalculator struct {
+	service *blockchain.BlockchainService
+	prices  price.PriceSource
+	native  blockchain.TokenInfo
+}
+
+// NewProfitCalculator creates a calculator that estimates costs through
+// service and values them with prices. native is the token gas is paid in.
+func NewProfitCalculator(service *blockchain.BlockchainService, prices price.PriceSource, native blockchain.TokenInfo) *ProfitCalculator {
+	return &ProfitCalculator{
+		service: service,
+		prices:  prices,
+		native:  native,
+	}
+}
+
+// Calculate estimates the transaction cost of an opportunity and records
+// its gross, fee and net profit in both USD and the opportunity's quote
+// token, which is the token the trade starts and ends with
+func (p *ProfitCalculator) Calculate(ctx context.Context, opp *blockchain.ArbitrageOpportunity) error {
+	quoteToken, ok := blockchain.FindToken(opp.FromToken)
+	if !ok {
+		return fmt.Errorf("unknown quote token %s", opp.FromToken)
+	}
+
+	quotePrice, err := p.prices.Price(ctx, quoteToken)
+	if err != nil {
+		return fmt.Errorf("failed to price %s: %w", quoteToken.Symbol, err)
+	}
+	if quotePrice.USD <= 0 {
+		return fmt.Errorf("invalid %s price %v", quoteToken.Symbol, quotePrice.USD)
+	}
+
+	nativePrice, err := p.prices.Price(ctx, p.native)
+	if err != nil {
+		return fmt.Errorf("failed to price %s: %w", p.native.Symbol, err)
+	}
+
+	// Opportunities reported by the contract carry only a USD profit, so
+	// size their transaction at one whole token
+	var gross, amount *big.Int
+	if opp.AmountIn != nil && opp.AmountOut != nil {
+		gross = new(big.Int).Sub(opp.AmountOut, opp.AmountIn)
+		amount = opp.AmountIn
+	} else {
+		gross = FromUnits(opp.ProfitUSD/quotePrice.USD, quoteToken.Decimals)
+		amount = FromUnits(1, quoteToken.Decimals)
+	}
+
+	cost, err := p.service.EstimateArbitrageCost(
+		ctx,
+		common.HexToAddress(opp.FromToken),
+		common.HexToAddress(opp.ToToken),
+		amount,
+		amount,
+		onChainHops(opp),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to estimate transaction cost: %w", err)
+	}
+
+	grossUSD := toUnits(gross, quoteToken.Decimals) * quotePrice.USD
+	feesUSD := toUnits(cost.Total(), nativeDecimals) * nativePrice.USD
+	fees := FromUnits(feesUSD/quotePrice.USD, quoteToken.Decimals)
+
+	opp.Profit = &blockchain.ProfitBreakdown{
+		QuoteToken:     quoteToken.Address,
+		GrossProfit:    gross,
+		Fees:           fees,
+		NetProfit:      new(big.Int).Sub(gross, fees),
+		GrossProfitUSD: grossUSD,
+		FeesUSD:        feesUSD,
+		NetProfitUSD:   grossUSD - feesUSD,
+		Cost:           cost,
+	}
+	return nil
+}
+
+// onChainHops returns the number of swaps an opportunity executes on chain.
+// Only the DEX leg of a cex-dex spread is on chain, and opportunities
+// reported by the contract were executed as a single call.
+func onChainHops(opp *blockchain.ArbitrageOpportunity) int {
+	if opp.Type == blockchain.OpportunityCEXDEX || len(opp.Legs) == 0 {
+		return 1
+	}
+	return len(opp.Legs)
+}
+
+// CalculateAll runs Calculate for every opportunity whose Profit is not yet
+// set, concurrently. Failures are logged and leave that opportunity's Profit
+// unset.
+func (p *ProfitCalculator) CalculateAll(ctx context.Context, opps []blockchain.ArbitrageOpportunity) {
+	var wg sync.WaitGroup
+	for i := range opps {
+		if opps[i].Profit != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(opp *blockchain.ArbitrageOpportunity) {
+			defer wg.Done()
+			if err := p.Calculate(ctx, opp); err != nil {
+				log.Printf("Failed to calculate net profit for opportunity %s: %v", opp.ID, err)
+			}
+		}(&opps[i])
+	}
+	wg.Wait()
+}
+
+// FromUnits converts whole token units to a raw token amount
+func FromUnits(value float64, decimals uint8) *big.Int {
+	raw := new(big.Float).Mul(big.NewFloat(value), big.NewFloat(math.Pow10(int(decimals))))
+	amount, _ := raw.Int(nil)
+	return amount
+}
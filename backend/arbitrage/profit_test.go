@@ -0,0 +1,47 @@
+package arbitrage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/blockchain/simulated/simtest"
+	"github.com/arbie-buckets/price/pricetest"
+)
+
+func TestCalculateCostsEveryOnChainHop(t *testing.T) {
+	service := simtest.New(t, simtest.Options{}).Service
+	usdc, _ := blockchain.FindToken("USDC")
+	eth, _ := blockchain.FindToken("ETH")
+	calculator := NewProfitCalculator(service, pricetest.Fixed{"USDC": 1, "ETH": 2500}, eth)
+
+	opportunity := func(oppType string, legs int) *blockchain.ArbitrageOpportunity {
+		return &blockchain.ArbitrageOpportunity{
+			Type:      oppType,
+			FromToken: usdc.Address,
+			ToToken:   eth.Address,
+			AmountIn:  big.NewInt(1_000_000),
+			AmountOut: big.NewInt(1_010_000),
+			Legs:      make([]blockchain.OpportunityLeg, legs),
+		}
+	}
+
+	ctx := context.Background()
+	// Only the DEX leg of a spread is executed on chain
+	spread := opportunity(blockchain.OpportunityCEXDEX, 2)
+	route := opportunity(blockchain.OpportunityMultiHop, 3)
+	for _, opp := range []*blockchain.ArbitrageOpportunity{spread, route} {
+		if err := calculator.Calculate(ctx, opp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	single, multi := spread.Profit.Cost, route.Profit.Cost
+	if multi.L2GasLimit != 3*single.L2GasLimit {
+		t.Errorf("3-hop route gas limit = %d, want 3 × %d", multi.L2GasLimit, single.L2GasLimit)
+	}
+	if route.Profit.FeesUSD <= spread.Profit.FeesUSD {
+		t.Errorf("3-hop route fees %v USD are not above the single swap's %v USD", route.Profit.FeesUSD, spread.Profit.FeesUSD)
+	}
+}
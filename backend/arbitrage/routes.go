@@ -0,0 +1,365 @@
+package arbitrage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/venue"
+)
+
+const (
+	// DefaultMaxHops bounds the length of the cycles searched
+	DefaultMaxHops = 3
+
+	// DefaultMaxReserveShare caps the input at this share of the first pool
+	DefaultMaxReserveShare = 0.1
+
+	// DefaultSearchIterations is the number of golden-section steps used to
+	// find the most profitable input size
+	DefaultSearchIterations = 40
+
+	// DefaultMaxResults caps how many routes are returned per search
+	DefaultMaxResults = 20
+)
+
+// Config controls the route search
+type Config struct {
+	// MaxHops is the maximum number of legs in a cycle
+	MaxHops int
+
+	// ReferenceToken is the token profits are valued in for ranking,
+	// typically a USD stablecoin
+	ReferenceToken blockchain.TokenInfo
+
+	// MaxReserveShare caps the input at a share of the first pool's reserve
+	MaxReserveShare float64
+
+	// SearchIterations is the number of steps of the input size search
+	SearchIterations int
+
+	// MaxResults caps the number of routes returned
+	MaxResults int
+}
+
+// DefaultConfig returns the route search defaults, valuing profit in USDC
+func DefaultConfig() Config {
+	reference, _ := blockchain.FindToken("USDC")
+	return Config{
+		MaxHops:          DefaultMaxHops,
+		ReferenceToken:   reference,
+		MaxReserveShare:  DefaultMaxReserveShare,
+		SearchIterations: DefaultSearchIterations,
+		MaxResults:       DefaultMaxResults,
+	}
+}
+
+// Route is a profitable cycle together with its optimal input size
+type Route struct {
+	Legs        []*Edge
+	Amounts     []*big.Int // Amounts[i] is the input of leg i; the last entry is the final output
+	AmountIn    *big.Int
+	AmountOut   *big.Int
+	Profit      *big.Int // AmountOut - AmountIn, in units of the start token
+	ProfitValue float64  // Profit valued in the reference token
+	SizedBy     string   // Limit that determined AmountIn, set by Sizer
+	PriceImpact float64  // Expected price impact in percent, set by Sizer
+	Timestamp   time.Time
+}
+
+// Start returns the token the route begins and ends with
+func (r *Route) Start() blockchain.TokenInfo {
+	return r.Legs[0].Pair.Base
+}
+
+// Opportunity converts the route into the API opportunity model
+func (r *Route) Opportunity() blockchain.ArbitrageOpportunity {
+	legs := make([]blockchain.OpportunityLeg, len(r.Legs))
+	keys := make([]string, len(r.Legs))
+	for i, edge := range r.Legs {
+		legs[i] = blockchain.OpportunityLeg{
+			Venue:     edge.Venue.ID(),
+			TokenIn:   edge.Pair.Base.Address,
+			TokenOut:  edge.Pair.Quote.Address,
+			AmountIn:  r.Amounts[i],
+			AmountOut: r.Amounts[i+1],
+		}
+		keys[i] = edge.Venue.ID() + ":" + edge.Pair.String()
+	}
+
+	oppType := blockchain.OpportunityMultiHop
+	if len(r.Legs) == 2 {
+		oppType = blockchain.OpportunityDirect
+	}
+
+	percentage, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(r.Profit),
+		new(big.Float).SetInt(r.AmountIn),
+	).Float64()
+
+	id := sha256.Sum256([]byte(strings.Join(keys, "|")))
+
+	return blockchain.ArbitrageOpportunity{
+		ID:          hex.EncodeToString(id[:8]),
+		Type:        oppType,
+		FromToken:   r.Start().Address,
+		ToToken:     r.Legs[0].Pair.Quote.Address,
+		ProfitUSD:   r.ProfitValue,
+		Percentage:  percentage * 100,
+		Timestamp:   r.Timestamp.Unix(),
+		AmountIn:    r.AmountIn,
+		AmountOut:   r.AmountOut,
+		Legs:        legs,
+		SizedBy:     r.SizedBy,
+		PriceImpact: r.PriceImpact,
+	}
+}
+
+// RouteFinder searches the venue graph for profitable cycles
+type RouteFinder struct {
+	registry *venue.Registry
+
+	mutex  sync.RWMutex
+	tokens []blockchain.TokenInfo
+	config Config
+}
+
+// NewRouteFinder creates a route finder over the given venues and tokens
+func NewRouteFinder(registry *venue.Registry, tokens []blockchain.TokenInfo, config Config) *RouteFinder {
+	return &RouteFinder{
+		registry: registry,
+		tokens:   tokens,
+		config:   config.withDefaults(),
+	}
+}
+
+// Reconfigure replaces the tokens and settings used by later searches
+func (f *RouteFinder) Reconfigure(tokens []blockchain.TokenInfo, config Config) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.tokens = tokens
+	f.config = config.withDefaults()
+}
+
+// withDefaults fills unset fields from DefaultConfig
+func (config Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if config.MaxHops < 2 {
+		config.MaxHops = defaults.MaxHops
+	}
+	if config.ReferenceToken.Address == "" {
+		config.ReferenceToken = defaults.ReferenceToken
+	}
+	if config.MaxReserveShare <= 0 || config.MaxReserveShare > 1 {
+		config.MaxReserveShare = defaults.MaxReserveShare
+	}
+	if config.SearchIterations <= 0 {
+		config.SearchIterations = defaults.SearchIterations
+	}
+	if config.MaxResults <= 0 {
+		config.MaxResults = defaults.MaxResults
+	}
+	return config
+}
+
+// Find builds a fresh graph and returns the profitable cycles, ranked by
+// profit after swap fees valued in the reference token
+func (f *RouteFinder) Find(ctx context.Context) ([]Route, error) {
+	f.mutex.RLock()
+	tokens, config := f.tokens, f.config
+	f.mutex.RUnlock()
+
+	graph := BuildGraph(ctx, f.registry, tokens)
+
+	// The same cycle is found once per token it passes through; keep only
+	// the rotation whose start token yields the most valuable profit
+	best := make(map[string]Route)
+	for _, start := range tokens {
+		for _, cycle := range graph.Cycles(start, config.MaxHops) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			route, ok := optimize(ctx, cycle, config)
+			if !ok {
+				continue
+			}
+			route.ProfitValue = referenceValue(ctx, graph, start, route.Profit, config.ReferenceToken)
+
+			key := cycleKey(cycle)
+			if existing, seen := best[key]; !seen || route.ProfitValue > existing.ProfitValue {
+				best[key] = route
+			}
+		}
+	}
+
+	routes := make([]Route, 0, len(best))
+	for _, route := range best {
+		routes = append(routes, route)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].ProfitValue > routes[j].ProfitValue
+	})
+	if len(routes) > config.MaxResults {
+		routes = routes[:config.MaxResults]
+	}
+
+	return routes, nil
+}
+
+// Simulate runs amountIn through every leg of the cycle and returns the
+// amount entering each leg followed by the final output
+func Simulate(ctx context.Context, legs []*Edge, amountIn *big.Int) ([]*big.Int, error) {
+	amounts := make([]*big.Int, 0, len(legs)+1)
+	amounts = append(amounts, amountIn)
+
+	current := amountIn
+	for _, leg := range legs {
+		out, err := leg.AmountOut(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, out)
+		current = out
+	}
+
+	return amounts, nil
+}
+
+// optimize finds the input size that maximizes the cycle's profit. Profit is
+// concave in the input for constant product pools, so a golden-section
+// search between a dust amount and the reserve cap converges on the optimum.
+func optimize(ctx context.Context, legs []*Edge, config Config) (Route, bool) {
+	upper := maxInput(legs[0], config.MaxReserveShare)
+	if upper <= 0 {
+		return Route{}, false
+	}
+	lower := upper / 1e6
+
+	profitAt := func(x float64) float64 {
+		amounts, err := Simulate(ctx, legs, floatToInt(x))
+		if err != nil {
+			return math.Inf(-1)
+		}
+		profit, _ := new(big.Float).SetInt(new(big.Int).Sub(amounts[len(amounts)-1], amounts[0])).Float64()
+		return profit
+	}
+
+	// If even a dust-sized trade loses, no larger trade can win
+	if profitAt(lower) <= 0 {
+		return Route{}, false
+	}
+
+	best := goldenSectionMax(lower, upper, config.SearchIterations, profitAt)
+
+	amountIn := floatToInt(best)
+	amounts, err := Simulate(ctx, legs, amountIn)
+	if err != nil {
+		return Route{}, false
+	}
+	amountOut := amounts[len(amounts)-1]
+	profit := new(big.Int).Sub(amountOut, amountIn)
+	if profit.Sign() <= 0 {
+		return Route{}, false
+	}
+
+	return Route{
+		Legs:      legs,
+		Amounts:   amounts,
+		AmountIn:  amountIn,
+		AmountOut: amountOut,
+		Profit:    profit,
+		Timestamp: time.Now(),
+	}, true
+}
+
+// referenceValue converts an amount of token into the reference token, using the
+// best direct edge in the graph
+func referenceValue(ctx context.Context, graph *Graph, token blockchain.TokenInfo, amount *big.Int, reference blockchain.TokenInfo) float64 {
+	if tokenKey(token) == tokenKey(reference) {
+		return toUnits(amount, reference.Decimals)
+	}
+
+	best := new(big.Int)
+	for _, edge := range graph.Edges(token) {
+		if edge.To() != tokenKey(reference) {
+			continue
+		}
+		out, err := edge.AmountOut(ctx, amount)
+		if err == nil && out.Cmp(best) > 0 {
+			best = out
+		}
+	}
+	return toUnits(best, reference.Decimals)
+}
+
+// cycleKey identifies a cycle independently of the token it starts from
+func cycleKey(legs []*Edge) string {
+	keys := make([]string, len(legs))
+	first := 0
+	for i, edge := range legs {
+		keys[i] = edge.Venue.ID() + ":" + edge.From() + ">" + edge.To()
+		if keys[i] < keys[first] {
+			first = i
+		}
+	}
+
+	rotated := append(keys[first:], keys[:first]...)
+	return strings.Join(rotated, "|")
+}
+
+// maxInput returns the largest input considered for a cycle starting on edge
+func maxInput(edge *Edge, share float64) float64 {
+	if liquidity := edge.Liquidity(); liquidity != nil {
+		reserve, _ := new(big.Float).SetInt(liquidity.BaseReserve).Float64()
+		return reserve * share
+	}
+	// Without depth information fall back to a single whole token
+	return math.Pow10(int(edge.Pair.Base.Decimals))
+}
+
+// goldenSectionMax returns the x in [lo, hi] maximizing the unimodal f
+func goldenSectionMax(lo, hi float64, iterations int, f func(float64) float64) float64 {
+	ratio := (math.Sqrt(5) - 1) / 2
+
+	a, b := lo, hi
+	c := b - ratio*(b-a)
+	d := a + ratio*(b-a)
+	fc, fd := f(c), f(d)
+
+	for i := 0; i < iterations; i++ {
+		if fc > fd {
+			b, d, fd = d, c, fc
+			c = b - ratio*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + ratio*(b-a)
+			fd = f(d)
+		}
+	}
+
+	return (a + b) / 2
+}
+
+// floatToInt converts a non-negative raw token amount to an integer
+func floatToInt(x float64) *big.Int {
+	amount, _ := new(big.Float).SetFloat64(x).Int(nil)
+	return amount
+}
+
+// toUnits converts a raw token amount to whole units
+func toUnits(amount *big.Int, decimals uint8) float64 {
+	value := new(big.Float).SetInt(amount)
+	value.Quo(value, new(big.Float).SetFloat64(math.Pow10(int(decimals))))
+	result, _ := value.Float64()
+	return result
+}
@@ -0,0 +1,85 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+// Scanner finds routes across venues and sizes them for execution
+type Scanner struct {
+	finder *RouteFinder
+	sizer  *Sizer
+}
+
+// NewScanner creates a scanner from a route finder and a sizer
+func NewScanner(finder *RouteFinder, sizer *Sizer) *Scanner {
+	return &Scanner{
+		finder: finder,
+		sizer:  sizer,
+	}
+}
+
+// FindOpportunities searches for profitable routes, sizes each one against
+// the configured limits and returns those still profitable at that size,
+// most profitable first
+func (s *Scanner) FindOpportunities(ctx context.Context) ([]blockchain.ArbitrageOpportunity, error) {
+	routes, err := s.finder.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opportunities := make([]blockchain.ArbitrageOpportunity, 0, len(routes))
+	for i := range routes {
+		if err := s.sizer.Size(ctx, &routes[i]); err != nil {
+			if !errors.Is(err, ErrNotProfitable) {
+				log.Printf("Failed to size route: %v", err)
+			}
+			continue
+		}
+		opportunities = append(opportunities, routes[i].Opportunity())
+	}
+
+	// Sizing changes each route's profit, so the search order no longer holds
+	sort.SliceStable(opportunities, func(i, j int) bool {
+		return opportunities[i].ProfitUSD > opportunities[j].ProfitUSD
+	})
+	return opportunities, nil
+}
+
+// SizeOpportunities sizes opportunities found elsewhere, such as those
+// reported by the contract, against the same limits as routes. Those that
+// carry no amounts are kept as reported; those that are not profitable at
+// their size, or cannot be sized, are dropped.
+func (s *Scanner) SizeOpportunities(ctx context.Context, opps []blockchain.ArbitrageOpportunity) []blockchain.ArbitrageOpportunity {
+	sized := make([]blockchain.ArbitrageOpportunity, 0, len(opps))
+	for i := range opps {
+		if opps[i].AmountIn == nil {
+			sized = append(sized, opps[i])
+			continue
+		}
+		if err := s.sizer.SizeOpportunity(ctx, &opps[i]); err != nil {
+			if !errors.Is(err, ErrNotProfitable) {
+				log.Printf("Failed to size opportunity %s: %v", opps[i].ID, err)
+			}
+			continue
+		}
+		sized = append(sized, opps[i])
+	}
+	return sized
+}
+
+// SortByNetProfit orders opportunities by net profit, best first. Those
+// whose net profit could not be calculated come last.
+func SortByNetProfit(opps []blockchain.ArbitrageOpportunity) {
+	sort.SliceStable(opps, func(i, j int) bool {
+		a, b := opps[i].Profit, opps[j].Profit
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.NetProfitUSD > b.NetProfitUSD
+	})
+}
@@ -0,0 +1,55 @@
+package arbitrage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/price/pricetest"
+)
+
+func TestSizeOpportunities(t *testing.T) {
+	usdc, _ := blockchain.FindToken("USDC")
+	sizer := NewSizer(nil, pricetest.Fixed{"USDC": 1}, SizingLimits{MaxTradeUSD: 100})
+	scanner := NewScanner(nil, sizer)
+
+	opps := scanner.SizeOpportunities(context.Background(), []blockchain.ArbitrageOpportunity{
+		{ID: "large", FromToken: usdc.Address, AmountIn: big.NewInt(500_000_000), AmountOut: big.NewInt(505_000_000)},
+		{ID: "small", FromToken: usdc.Address, AmountIn: big.NewInt(50_000_000), AmountOut: big.NewInt(50_400_000)},
+		{ID: "loss", FromToken: usdc.Address, AmountIn: big.NewInt(50_000_000), AmountOut: big.NewInt(49_000_000)},
+		{ID: "unsized", FromToken: usdc.Address, ProfitUSD: 3},
+	})
+	if len(opps) != 3 {
+		t.Fatalf("kept %d opportunities, want the 2 profitable ones and the unsized one", len(opps))
+	}
+
+	// Clamped to the trade limit, with the output scaled to match
+	large := opps[0]
+	if large.AmountIn.Int64() != 100_000_000 || large.AmountOut.Int64() != 101_000_000 || large.SizedBy != SizedTradeLimit {
+		t.Errorf("large sized to %v -> %v by %s, want 100000000 -> 101000000 by %s", large.AmountIn, large.AmountOut, large.SizedBy, SizedTradeLimit)
+	}
+	small := opps[1]
+	if small.AmountIn.Int64() != 50_000_000 || small.SizedBy != SizedOptimal {
+		t.Errorf("small sized to %v by %s, want it unchanged", small.AmountIn, small.SizedBy)
+	}
+	unsized := opps[2]
+	if unsized.ID != "unsized" || unsized.AmountIn != nil || unsized.SizedBy != "" {
+		t.Errorf("unsized opportunity = %+v, want it kept as reported", unsized)
+	}
+}
+
+func TestSortByNetProfit(t *testing.T) {
+	opps := []blockchain.ArbitrageOpportunity{
+		{ID: "uncosted"},
+		{ID: "low", Profit: &blockchain.ProfitBreakdown{NetProfitUSD: 1}},
+		{ID: "high", Profit: &blockchain.ProfitBreakdown{NetProfitUSD: 5}},
+	}
+	SortByNetProfit(opps)
+
+	for i, want := range []string{"high", "low", "uncosted"} {
+		if opps[i].ID != want {
+			t.Errorf("position %d is %s, want %s", i, opps[i].ID, want)
+		}
+	}
+}
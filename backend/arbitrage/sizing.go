@@ -0,0 +1,268 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/price"
+)
+
+// Reasons reported for the size chosen for a route
+const (
+	SizedOptimal    = "optimal"
+	SizedWallet     = "wallet-balance"
+	SizedTradeLimit = "trade-limit"
+	SizedPoolShare  = "pool-share"
+)
+
+const (
+	// DefaultMaxTradeUSD matches the contract's default tradingAmount
+	DefaultMaxTradeUSD = 1000
+
+	// DefaultMaxPoolShare caps each leg's input at this share of its pool
+	DefaultMaxPoolShare = 0.05
+
+	// poolShareSearchSteps is the number of bisection steps used to find the
+	// largest input that respects the pool share cap on every leg
+	poolShareSearchSteps = 64
+)
+
+// ErrNotProfitable is returned when no allowed size yields a profit
+var ErrNotProfitable = errors.New("route is not profitable within sizing limits")
+
+// SizingLimits bounds the input size chosen for a route
+type SizingLimits struct {
+	// MaxTradeUSD is the per-trade limit in USD; zero disables it
+	MaxTradeUSD float64
+
+	// MaxPoolShare caps the input of every leg at this share of the pool's
+	// reserve of the token being sold; zero disables it
+	MaxPoolShare float64
+}
+
+// DefaultSizingLimits returns the default per-trade and pool share limits
+func DefaultSizingLimits() SizingLimits {
+	return SizingLimits{
+		MaxTradeUSD:  DefaultMaxTradeUSD,
+		MaxPoolShare: DefaultMaxPoolShare,
+	}
+}
+
+// Sizer chooses the input size of each route. Transaction cost does not
+// depend on size, so the net-profit-maximizing input is the gross optimum
+// found by the route search, clamped to the wallet balance, the per-trade
+// limit and the pool share limit.
+type Sizer struct {
+	service *blockchain.BlockchainService
+	prices  price.PriceSource
+
+	mutex  sync.RWMutex
+	limits SizingLimits
+}
+
+// NewSizer creates a sizer. service may be nil, in which case the wallet
+// balance is not used as a cap.
+func NewSizer(service *blockchain.BlockchainService, prices price.PriceSource, limits SizingLimits) *Sizer {
+	return &Sizer{
+		service: service,
+		prices:  prices,
+		limits:  limits,
+	}
+}
+
+// SetLimits replaces the limits applied to later routes
+func (s *Sizer) SetLimits(limits SizingLimits) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.limits = limits
+}
+
+// Size resizes the route in place and records the chosen size, the limit
+// that bound it and the expected price impact
+func (s *Sizer) Size(ctx context.Context, route *Route) error {
+	amountIn, sizedBy := route.AmountIn, SizedOptimal
+	for _, limit := range s.caps(ctx, route.Start(), route.Legs) {
+		if limit.amount.Cmp(amountIn) < 0 {
+			amountIn, sizedBy = limit.amount, limit.reason
+		}
+	}
+
+	amounts, err := Simulate(ctx, route.Legs, amountIn)
+	if err != nil {
+		return err
+	}
+	amountOut := amounts[len(amounts)-1]
+	profit := new(big.Int).Sub(amountOut, amountIn)
+	if amountIn.Sign() <= 0 || profit.Sign() <= 0 {
+		return ErrNotProfitable
+	}
+
+	// Rescale the valued profit to the new size
+	if route.Profit.Sign() > 0 {
+		ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(profit), new(big.Float).SetInt(route.Profit)).Float64()
+		route.ProfitValue *= ratio
+	}
+
+	route.Amounts = amounts
+	route.AmountIn = amountIn
+	route.AmountOut = amountOut
+	route.Profit = profit
+	route.SizedBy = sizedBy
+	route.PriceImpact = priceImpact(ctx, route.Legs, amounts)
+	return nil
+}
+
+// SizeOpportunity clamps an opportunity that was not found by the route
+// search, such as a trade reported by the contract, to the wallet balance
+// and the per-trade limit. Its pools are unknown, so the output is scaled
+// in proportion to the input and the pool share limit does not apply.
+func (s *Sizer) SizeOpportunity(ctx context.Context, opp *blockchain.ArbitrageOpportunity) error {
+	if opp.AmountIn == nil || opp.AmountOut == nil || opp.AmountIn.Sign() <= 0 {
+		return fmt.Errorf("opportunity %s has no amounts to size", opp.ID)
+	}
+	start, ok := blockchain.FindToken(opp.FromToken)
+	if !ok {
+		return fmt.Errorf("unknown token %s", opp.FromToken)
+	}
+
+	amountIn, sizedBy := opp.AmountIn, SizedOptimal
+	for _, limit := range s.caps(ctx, start, nil) {
+		if limit.amount.Cmp(amountIn) < 0 {
+			amountIn, sizedBy = limit.amount, limit.reason
+		}
+	}
+
+	amountOut := new(big.Int).Mul(opp.AmountOut, amountIn)
+	amountOut.Quo(amountOut, opp.AmountIn)
+	if amountIn.Sign() <= 0 || amountOut.Cmp(amountIn) <= 0 {
+		return ErrNotProfitable
+	}
+
+	opp.AmountIn = amountIn
+	opp.AmountOut = amountOut
+	opp.SizedBy = sizedBy
+	return nil
+}
+
+// sizeCap is an upper bound on the input and the limit it comes from
+type sizeCap struct {
+	amount *big.Int
+	reason string
+}
+
+// caps returns every applicable upper bound on the input of a trade
+// starting with start. The pool share limit applies only when the legs are
+// known.
+func (s *Sizer) caps(ctx context.Context, start blockchain.TokenInfo, legs []*Edge) []sizeCap {
+	var caps []sizeCap
+
+	s.mutex.RLock()
+	limits := s.limits
+	s.mutex.RUnlock()
+
+	if s.service != nil {
+		balance, err := s.service.GetTokenBalance(ctx, start.HexAddress())
+		if err != nil {
+			log.Printf("Failed to get %s balance for sizing: %v", start.Symbol, err)
+		} else {
+			caps = append(caps, sizeCap{amount: balance, reason: SizedWallet})
+		}
+	}
+
+	if limits.MaxTradeUSD > 0 {
+		startPrice, err := s.prices.Price(ctx, start)
+		if err != nil || startPrice.USD <= 0 {
+			log.Printf("Failed to price %s for trade limit: %v", start.Symbol, err)
+		} else {
+			caps = append(caps, sizeCap{
+				amount: FromUnits(limits.MaxTradeUSD/startPrice.USD, start.Decimals),
+				reason: SizedTradeLimit,
+			})
+		}
+	}
+
+	if limits.MaxPoolShare > 0 && len(legs) > 0 {
+		if limit, err := maxPoolShareInput(ctx, legs, limits.MaxPoolShare); err == nil {
+			caps = append(caps, sizeCap{amount: limit, reason: SizedPoolShare})
+		}
+	}
+
+	return caps
+}
+
+// maxPoolShareInput finds the largest input for which every leg sells at
+// most share of its pool's reserve. Leg inputs grow with the route input,
+// so the bound is found by bisection.
+func maxPoolShareInput(ctx context.Context, legs []*Edge, share float64) (*big.Int, error) {
+	first := legs[0].Liquidity()
+	if first == nil {
+		return nil, fmt.Errorf("no liquidity snapshot for first leg")
+	}
+
+	withinShare := func(amountIn *big.Int) bool {
+		amounts, err := Simulate(ctx, legs, amountIn)
+		if err != nil {
+			return false
+		}
+		for i, leg := range legs {
+			liquidity := leg.Liquidity()
+			if liquidity == nil {
+				continue
+			}
+			limit := fromShare(liquidity.BaseReserve, share)
+			if amounts[i].Cmp(limit) > 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	low, high := big.NewInt(0), fromShare(first.BaseReserve, share)
+	if withinShare(high) {
+		return high, nil
+	}
+	for i := 0; i < poolShareSearchSteps && new(big.Int).Sub(high, low).Cmp(big.NewInt(1)) > 0; i++ {
+		mid := new(big.Int).Add(low, high)
+		mid.Rsh(mid, 1)
+		if withinShare(mid) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return low, nil
+}
+
+// priceImpact returns how far, in percent, the route's realized rate falls
+// short of its marginal rate at a dust-sized input. Swap fees affect both
+// rates equally, so the result isolates slippage.
+func priceImpact(ctx context.Context, legs []*Edge, amounts []*big.Int) float64 {
+	amountIn, amountOut := amounts[0], amounts[len(amounts)-1]
+
+	dust := new(big.Int).Div(amountIn, big.NewInt(1000000))
+	if dust.Sign() <= 0 {
+		dust = big.NewInt(1)
+	}
+	dustAmounts, err := Simulate(ctx, legs, dust)
+	if err != nil || dustAmounts[len(dustAmounts)-1].Sign() <= 0 {
+		return 0
+	}
+
+	spot := new(big.Float).Quo(new(big.Float).SetInt(dustAmounts[len(dustAmounts)-1]), new(big.Float).SetInt(dust))
+	realized := new(big.Float).Quo(new(big.Float).SetInt(amountOut), new(big.Float).SetInt(amountIn))
+	ratio, _ := new(big.Float).Quo(realized, spot).Float64()
+
+	return (1 - ratio) * 100
+}
+
+// fromShare returns share of a raw token amount
+func fromShare(amount *big.Int, share float64) *big.Int {
+	result, _ := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(share)).Int(nil)
+	return result
+}
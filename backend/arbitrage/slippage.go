@@ -0,0 +1,177 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/venue"
+)
+
+const (
+	// DefaultSlippageToleranceBps is the default tolerance below the quoted
+	// output accepted by an execution
+	DefaultSlippageToleranceBps = 50
+
+	// DefaultMaxQuoteAgeBlocks is the default number of blocks a quote stays
+	// valid for execution
+	DefaultMaxQuoteAgeBlocks = 3
+
+	// MaxToleranceBps is 100%; tolerances must be below it, or any output
+	// would be accepted
+	MaxToleranceBps = 10000
+)
+
+var (
+	// ErrStaleQuote is returned when a quote is older than the allowed
+	// number of blocks
+	ErrStaleQuote = errors.New("quote is stale")
+
+	// ErrNoQuote is returned when no venue can quote the trade
+	ErrNoQuote = errors.New("no venue could quote the trade")
+)
+
+// SlippageConfig controls how minReturn is derived for executions
+type SlippageConfig struct {
+	ToleranceBps      uint32
+	MaxQuoteAgeBlocks uint64
+}
+
+// DefaultSlippageConfig returns the default tolerance and quote age
+func DefaultSlippageConfig() SlippageConfig {
+	return SlippageConfig{
+		ToleranceBps:      DefaultSlippageToleranceBps,
+		MaxQuoteAgeBlocks: DefaultMaxQuoteAgeBlocks,
+	}
+}
+
+// GuardedQuote is a fresh quote together with the minReturn derived from it
+type GuardedQuote struct {
+	Quote        *venue.Quote
+	ToleranceBps uint32
+
+	// MinReturn is the value to send: the caller's override when given,
+	// otherwise QuotedMinReturn
+	MinReturn       *big.Int
+	QuotedMinReturn *big.Int
+	Overridden      bool
+}
+
+// SlippageGuard prices executions from fresh quotes and refuses to send
+// them once the quote has aged past the configured number of blocks
+type SlippageGuard struct {
+	registry *venue.Registry
+	service  *blockchain.BlockchainService
+
+	mutex  sync.RWMutex
+	config SlippageConfig
+}
+
+// NewSlippageGuard creates a guard quoting through the registered venues
+func NewSlippageGuard(registry *venue.Registry, service *blockchain.BlockchainService, config SlippageConfig) *SlippageGuard {
+	return &SlippageGuard{
+		registry: registry,
+		service:  service,
+		config:   config,
+	}
+}
+
+// SetConfig replaces the tolerance and quote age used by later executions
+func (g *SlippageGuard) SetConfig(config SlippageConfig) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.config = config
+}
+
+// currentConfig returns the configuration in effect
+func (g *SlippageGuard) currentConfig() SlippageConfig {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	return g.config
+}
+
+// Prepare takes the best quote across venues and derives minReturn from it.
+// toleranceBps overrides the configured tolerance when non-nil and
+// minReturn overrides the derived value when non-nil.
+func (g *SlippageGuard) Prepare(ctx context.Context, pair venue.Pair, amountIn *big.Int, toleranceBps *uint32, minReturn *big.Int) (*GuardedQuote, error) {
+	tolerance := g.currentConfig().ToleranceBps
+	if toleranceBps != nil {
+		tolerance = *toleranceBps
+	}
+	if tolerance >= MaxToleranceBps {
+		return nil, fmt.Errorf("slippage tolerance %d bps must be below %d", tolerance, MaxToleranceBps)
+	}
+
+	// Record the head before quoting so the quote's age is never understated
+	blockNumber, err := g.service.GetBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := g.bestQuote(ctx, pair, amountIn)
+	if err != nil {
+		return nil, err
+	}
+	quote.BlockNumber = blockNumber
+
+	guarded := &GuardedQuote{
+		Quote:           quote,
+		ToleranceBps:    tolerance,
+		QuotedMinReturn: MinReturn(quote.AmountOut, tolerance),
+	}
+	guarded.MinReturn = guarded.QuotedMinReturn
+	if minReturn != nil {
+		guarded.MinReturn = minReturn
+		guarded.Overridden = true
+	}
+
+	return guarded, nil
+}
+
+// CheckFresh returns ErrStaleQuote if the chain has advanced more than the
+// allowed number of blocks since the quote was taken
+func (g *SlippageGuard) CheckFresh(ctx context.Context, quote *venue.Quote) error {
+	current, err := g.service.GetBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	maxAge := g.currentConfig().MaxQuoteAgeBlocks
+	if current > quote.BlockNumber && current-quote.BlockNumber > maxAge {
+		return fmt.Errorf("%w: quoted at block %d, chain is at %d (max age %d blocks)",
+			ErrStaleQuote, quote.BlockNumber, current, maxAge)
+	}
+	return nil
+}
+
+// bestQuote returns the quote with the highest output across the on-chain
+// venues the contract can execute against
+func (g *SlippageGuard) bestQuote(ctx context.Context, pair venue.Pair, amountIn *big.Int) (*venue.Quote, error) {
+	var best *venue.Quote
+	for _, v := range g.registry.SupportingPair(pair) {
+		if v.Kind() != venue.KindDEX {
+			continue
+		}
+		quote, err := v.Quote(ctx, pair, amountIn)
+		if err != nil {
+			continue
+		}
+		if best == nil || quote.AmountOut.Cmp(best.AmountOut) > 0 {
+			best = quote
+		}
+	}
+
+	if best == nil || best.AmountOut.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoQuote, pair)
+	}
+	return best, nil
+}
+
+// MinReturn returns amountOut reduced by toleranceBps basis points
+func MinReturn(amountOut *big.Int, toleranceBps uint32) *big.Int {
+	minReturn := new(big.Int).Mul(amountOut, big.NewInt(int64(MaxToleranceBps-toleranceBps)))
+	return minReturn.Div(minReturn, big.NewInt(MaxToleranceBps))
+}
@@ -0,0 +1,414 @@
+package arbitrage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/cex"
+	"github.com/arbie-buckets/constants"
+	"github.com/arbie-buckets/price"
+	"github.com/arbie-buckets/venue"
+)
+
+// SizedBookDepth reports that larger sizes could not be filled by the CEX
+// order book
+const SizedBookDepth = "book-depth"
+
+const (
+	// DefaultMinSpreadBps is the minimum spread, net of DEX gas and CEX taker
+	// fees, reported as an opportunity
+	DefaultMinSpreadBps = 10
+
+	// DefaultMinSpreadProfitUSD is the minimum net profit reported
+	DefaultMinSpreadProfitUSD = 1
+
+	// DefaultSpreadBookDepth is the number of levels per side walked when
+	// sizing against a CEX book
+	DefaultSpreadBookDepth = 50
+)
+
+// DefaultSpreadSizesUSD are the trade sizes each spread is evaluated at
+var DefaultSpreadSizesUSD = []float64{100, 250, 500, 1000, 2500, 5000}
+
+// SpreadConfig controls the CEX–DEX spread scan
+type SpreadConfig struct {
+	// SizesUSD are the trade sizes evaluated, in USD. They are converted to
+	// the quote token at its oracle price.
+	SizesUSD []float64
+
+	// MaxTradeUSD drops sizes above the per-trade limit; zero disables it
+	MaxTradeUSD float64
+
+	// MinSpreadBps and MinProfitUSD are the thresholds a spread must clear
+	// after DEX gas and CEX taker fees
+	MinSpreadBps float64
+	MinProfitUSD float64
+
+	BookDepth int
+}
+
+// DefaultSpreadConfig returns the default sizes and thresholds
+func DefaultSpreadConfig() SpreadConfig {
+	return SpreadConfig{
+		SizesUSD:     DefaultSpreadSizesUSD,
+		MaxTradeUSD:  DefaultMaxTradeUSD,
+		MinSpreadBps: DefaultMinSpreadBps,
+		MinProfitUSD: DefaultMinSpreadProfitUSD,
+		BookDepth:    DefaultSpreadBookDepth,
+	}
+}
+
+// SpreadScanner compares Base DEX quotes against CEX order books for the
+// same asset. Each spread is sized by walking the CEX book and simulating
+// the DEX pool at a ladder of trade sizes.
+type SpreadScanner struct {
+	registry *venue.Registry
+	books    cex.BookSource
+	prices   price.PriceSource
+	profit   *ProfitCalculator
+
+	mutex  sync.RWMutex
+	tokens []blockchain.TokenInfo
+	config SpreadConfig
+
+	// watching is set while Watch keeps streamed books in latest, which
+	// then replace the book source
+	watching bool
+	latest   map[cex.Market]map[constants.Exchange]cex.BookUpdate
+}
+
+// NewSpreadScanner creates a scanner quoting DEX venues from registry
+// against the books of source. Quote tokens are valued with prices and net
+// profit is estimated with profit.
+func NewSpreadScanner(registry *venue.Registry, books cex.BookSource, tokens []blockchain.TokenInfo, prices price.PriceSource, profit *ProfitCalculator, config SpreadConfig) *SpreadScanner {
+	return &SpreadScanner{
+		registry: registry,
+		books:    books,
+		prices:   prices,
+		tokens:   tokens,
+		profit:   profit,
+		config:   config.withDefaults(),
+	}
+}
+
+// Reconfigure replaces the scanned tokens and thresholds for later scans
+func (s *SpreadScanner) Reconfigure(tokens []blockchain.TokenInfo, config SpreadConfig) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokens = tokens
+	s.config = config.withDefaults()
+}
+
+// Watch keeps the latest book from each update until ctx is cancelled or
+// updates is closed. Scans read these books instead of the book source
+// while watching.
+func (s *SpreadScanner) Watch(ctx context.Context, updates <-chan cex.BookUpdate) {
+	s.mutex.Lock()
+	s.watching = true
+	s.latest = make(map[cex.Market]map[constants.Exchange]cex.BookUpdate)
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		s.watching = false
+		s.latest = nil
+		s.mutex.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			s.mutex.Lock()
+			exchanges, ok := s.latest[update.Market]
+			if !ok {
+				exchanges = make(map[constants.Exchange]cex.BookUpdate)
+				s.latest[update.Market] = exchanges
+			}
+			exchanges[update.Exchange] = update
+			s.mutex.Unlock()
+		}
+	}
+}
+
+// orderBooks returns the books to scan: the fresh watched books truncated
+// to depth while watching, otherwise those of the book source
+func (s *SpreadScanner) orderBooks(ctx context.Context, depth int) []*cex.OrderBook {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if !s.watching {
+		return s.books.OrderBooks(ctx, depth)
+	}
+
+	var books []*cex.OrderBook
+	for _, exchanges := range s.latest {
+		for _, update := range exchanges {
+			if update.Stale() {
+				continue
+			}
+			book := *update.Book
+			book.Bids = book.Bids[:min(depth, len(book.Bids))]
+			book.Asks = book.Asks[:min(depth, len(book.Asks))]
+			books = append(books, &book)
+		}
+	}
+	return books
+}
+
+// withDefaults fills in the sizes and book depth when unset
+func (config SpreadConfig) withDefaults() SpreadConfig {
+	if len(config.SizesUSD) == 0 {
+		config.SizesUSD = DefaultSpreadSizesUSD
+	}
+	if config.BookDepth <= 0 {
+		config.BookDepth = DefaultSpreadBookDepth
+	}
+	return config
+}
+
+// spreadLeg is one side of a CEX–DEX trade
+type spreadLeg struct {
+	venue     string
+	tokenIn   blockchain.TokenInfo
+	tokenOut  blockchain.TokenInfo
+	amountIn  *big.Int
+	amountOut *big.Int
+}
+
+// spread is a CEX–DEX trade evaluated at one size. size and gross are in
+// units of the quote token, worth quoteUSD each.
+type spread struct {
+	book     *cex.OrderBook
+	edge     *Edge
+	buyDEX   bool
+	quoteUSD float64
+	sizeUSD  float64
+	size     float64
+	legs     [2]spreadLeg
+	gross    float64
+	sizedBy  string
+}
+
+// FindOpportunities returns the CEX–DEX spreads that clear the configured
+// thresholds, best first
+func (s *SpreadScanner) FindOpportunities(ctx context.Context) ([]blockchain.ArbitrageOpportunity, error) {
+	s.mutex.RLock()
+	tokens, config := s.tokens, s.config
+	s.mutex.RUnlock()
+
+	books := s.orderBooks(ctx, config.BookDepth)
+	if len(books) == 0 {
+		return nil, nil
+	}
+
+	var opportunities []blockchain.ArbitrageOpportunity
+	graphs := make(map[cex.Market]*Graph)
+	for _, book := range books {
+		base, quote, ok := cex.TokensForMarket(book.Market, tokens)
+		if !ok {
+			continue
+		}
+
+		// Sizes and thresholds are in USD, which the quote token need not be
+		quotePrice, err := s.prices.Price(ctx, quote)
+		if err != nil || quotePrice.USD <= 0 {
+			log.Printf("Failed to price %s for %s spreads: %v", quote.Symbol, book.Market, err)
+			continue
+		}
+
+		graph, ok := graphs[book.Market]
+		if !ok {
+			graph = BuildGraph(ctx, s.registry, []blockchain.TokenInfo{base, quote})
+			graphs[book.Market] = graph
+		}
+
+		// Buy on the DEX and sell into the CEX bids, or buy from the CEX asks
+		// and sell on the DEX
+		for _, edge := range graph.Edges(quote) {
+			if best, ok := s.best(ctx, book, edge, true, quotePrice.USD, config); ok {
+				if opp, ok := s.opportunity(ctx, best, config); ok {
+					opportunities = append(opportunities, opp)
+				}
+			}
+		}
+		for _, edge := range graph.Edges(base) {
+			if best, ok := s.best(ctx, book, edge, false, quotePrice.USD, config); ok {
+				if opp, ok := s.opportunity(ctx, best, config); ok {
+					opportunities = append(opportunities, opp)
+				}
+			}
+		}
+	}
+
+	sort.Slice(opportunities, func(i, j int) bool {
+		return opportunities[i].Profit.NetProfitUSD > opportunities[j].Profit.NetProfitUSD
+	})
+	return opportunities, nil
+}
+
+// best evaluates the spread at every allowed size and returns the one with
+// the highest gross profit. Gas does not depend on size, so this is also
+// the net optimum.
+func (s *SpreadScanner) best(ctx context.Context, book *cex.OrderBook, edge *Edge, buyDEX bool, quoteUSD float64, config SpreadConfig) (*spread, bool) {
+	sizes := config.sizes()
+
+	var best *spread
+	for i, size := range sizes {
+		candidate, filled, err := s.evaluate(ctx, book, edge, buyDEX, size, quoteUSD)
+		if err != nil {
+			log.Printf("Failed to quote %s against %s: %v", edge.Venue.ID(), book.Exchange, err)
+			return nil, false
+		}
+		if !filled {
+			if best != nil {
+				best.sizedBy = SizedBookDepth
+			}
+			break
+		}
+
+		if best == nil || candidate.gross > best.gross {
+			best = candidate
+			best.sizedBy = SizedOptimal
+			if i == len(sizes)-1 && config.MaxTradeUSD > 0 && size >= config.MaxTradeUSD {
+				best.sizedBy = SizedTradeLimit
+			}
+		}
+	}
+
+	if best == nil || best.gross <= 0 {
+		return nil, false
+	}
+	return best, true
+}
+
+// evaluate prices a round trip of sizeUSD, worth of the quote token at
+// quoteUSD, through the DEX edge and the CEX book, net of pool and taker
+// fees. filled is false when the book is too shallow for the size.
+func (s *SpreadScanner) evaluate(ctx context.Context, book *cex.OrderBook, edge *Edge, buyDEX bool, sizeUSD, quoteUSD float64) (*spread, bool, error) {
+	takerFee := 1 - float64(book.TakerFeeBps)/10000
+	exchange := book.Exchange.String()
+	size := sizeUSD / quoteUSD
+
+	result := &spread{book: book, edge: edge, buyDEX: buyDEX, quoteUSD: quoteUSD, sizeUSD: sizeUSD, size: size}
+	if buyDEX {
+		// Quote token -> base token on the DEX, then sell the base into the bids
+		quote, base := edge.Pair.Base, edge.Pair.Quote
+		amountIn := FromUnits(size, quote.Decimals)
+		bought, err := edge.AmountOut(ctx, amountIn)
+		if err != nil {
+			return nil, false, err
+		}
+
+		received, filled := book.SellBase(toUnits(bought, base.Decimals))
+		if !filled {
+			return nil, false, nil
+		}
+		received *= takerFee
+
+		result.legs = [2]spreadLeg{
+			{edge.Venue.ID(), quote, base, amountIn, bought},
+			{exchange, base, quote, bought, FromUnits(received, quote.Decimals)},
+		}
+		result.gross = received - size
+		return result, true, nil
+	}
+
+	// Buy the base from the asks, then sell it for the quote token on the DEX
+	base, quote := edge.Pair.Base, edge.Pair.Quote
+	bought, filled := book.BuyBase(size)
+	if !filled {
+		return nil, false, nil
+	}
+	bought *= takerFee
+
+	amountIn := FromUnits(bought, base.Decimals)
+	received, err := edge.AmountOut(ctx, amountIn)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result.legs = [2]spreadLeg{
+		{exchange, quote, base, FromUnits(size, quote.Decimals), amountIn},
+		{edge.Venue.ID(), base, quote, amountIn, received},
+	}
+	result.gross = toUnits(received, quote.Decimals) - size
+	return result, true, nil
+}
+
+// opportunity prices the DEX leg's gas and converts the spread into an
+// opportunity if it clears the thresholds
+func (s *SpreadScanner) opportunity(ctx context.Context, best *spread, config SpreadConfig) (blockchain.ArbitrageOpportunity, bool) {
+	quote, base := best.legs[0].tokenIn, best.legs[0].tokenOut
+
+	legs := make([]blockchain.OpportunityLeg, len(best.legs))
+	keys := []string{blockchain.OpportunityCEXDEX}
+	for i, leg := range best.legs {
+		legs[i] = blockchain.OpportunityLeg{
+			Venue:     leg.venue,
+			TokenIn:   leg.tokenIn.Address,
+			TokenOut:  leg.tokenOut.Address,
+			AmountIn:  leg.amountIn,
+			AmountOut: leg.amountOut,
+		}
+		keys = append(keys, leg.venue+":"+leg.tokenIn.Symbol+">"+leg.tokenOut.Symbol)
+	}
+	id := sha256.Sum256([]byte(strings.Join(keys, "|")))
+
+	opp := blockchain.ArbitrageOpportunity{
+		ID:         hex.EncodeToString(id[:8]),
+		Type:       blockchain.OpportunityCEXDEX,
+		FromToken:  quote.Address,
+		ToToken:    base.Address,
+		ProfitUSD:  best.gross * best.quoteUSD,
+		Percentage: best.gross / best.size * 100,
+		Timestamp:  time.Now().Unix(),
+		AmountIn:   best.legs[0].amountIn,
+		AmountOut:  best.legs[1].amountOut,
+		Legs:       legs,
+		SizedBy:    best.sizedBy,
+	}
+
+	// Only the DEX leg is executed on chain
+	if err := s.profit.Calculate(ctx, &opp); err != nil {
+		log.Printf("Failed to calculate net profit for spread %s: %v", opp.ID, err)
+		return opp, false
+	}
+
+	net := opp.Profit.NetProfitUSD
+	opp.SpreadBps = net / best.sizeUSD * 10000
+	if net < config.MinProfitUSD || opp.SpreadBps < config.MinSpreadBps {
+		return opp, false
+	}
+	return opp, true
+}
+
+// sizes returns the configured sizes within the per-trade limit, or the
+// limit itself when every size exceeds it
+func (config SpreadConfig) sizes() []float64 {
+	if config.MaxTradeUSD <= 0 {
+		return config.SizesUSD
+	}
+
+	var sizes []float64
+	for _, size := range config.SizesUSD {
+		if size <= config.MaxTradeUSD {
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) == 0 {
+		sizes = []float64{config.MaxTradeUSD}
+	}
+	return sizes
+}
@@ -0,0 +1,138 @@
+package arbitrage
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/blockchain/simulated/simtest"
+	"github.com/arbie-buckets/cex"
+	"github.com/arbie-buckets/constants"
+	"github.com/arbie-buckets/price/pricetest"
+	"github.com/arbie-buckets/venue"
+)
+
+// pricedVenue swaps tokens at the ratio of their fixed prices, with deep
+// liquidity on every pair
+type pricedVenue map[string]float64
+
+func (v pricedVenue) ID() string                        { return "priced" }
+func (v pricedVenue) Name() string                      { return "Priced" }
+func (v pricedVenue) Kind() venue.Kind                  { return venue.KindDEX }
+func (v pricedVenue) SupportsPair(pair venue.Pair) bool { return true }
+
+func (v pricedVenue) Quote(ctx context.Context, pair venue.Pair, amountIn *big.Int) (*venue.Quote, error) {
+	rate := v[pair.Base.Symbol] / v[pair.Quote.Symbol] * math.Pow10(int(pair.Quote.Decimals)-int(pair.Base.Decimals))
+	amountOut, _ := new(big.Float).Mul(new(big.Float).SetInt(amountIn), big.NewFloat(rate)).Int(nil)
+	return &venue.Quote{VenueID: v.ID(), Pair: pair, AmountIn: amountIn, AmountOut: amountOut, Timestamp: time.Now()}, nil
+}
+
+func (v pricedVenue) Liquidity(ctx context.Context, pair venue.Pair) (*venue.Liquidity, error) {
+	deep := new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
+	return &venue.Liquidity{VenueID: v.ID(), Pair: pair, BaseReserve: deep, QuoteReserve: deep, Timestamp: time.Now()}, nil
+}
+
+// staticBooks is a book source serving fixed books
+type staticBooks []*cex.OrderBook
+
+func (b staticBooks) OrderBooks(ctx context.Context, depth int) []*cex.OrderBook {
+	return b
+}
+
+func TestSpreadScannerValuesNonUSDQuote(t *testing.T) {
+	service := simtest.New(t, simtest.Options{}).Service
+	usdc, _ := blockchain.FindToken("USDC")
+	eth, _ := blockchain.FindToken("ETH")
+
+	// The quote token trades at half a dollar: the DEX sells ETH for 2000
+	// of it and the CEX bids 2100
+	prices := pricetest.Fixed{"USDC": 0.5, "ETH": 1000}
+	registry := venue.NewRegistry()
+	if err := registry.Register(pricedVenue{"USDC": 1, "ETH": 2000}); err != nil {
+		t.Fatal(err)
+	}
+	book := &cex.OrderBook{
+		Exchange: constants.BINANCE,
+		Market:   cex.Market{Base: "ETH", Quote: "USD"},
+		Bids:     []cex.Level{{Price: 2100, Size: 10}},
+		Asks:     []cex.Level{{Price: 2200, Size: 10}},
+	}
+
+	scanner := NewSpreadScanner(registry, staticBooks{book}, []blockchain.TokenInfo{eth, usdc}, prices,
+		NewProfitCalculator(service, prices, eth), SpreadConfig{SizesUSD: []float64{100}, MinProfitUSD: -1000, MinSpreadBps: -10000})
+	opps, err := scanner.FindOpportunities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opps) == 0 {
+		t.Fatal("found no spreads")
+	}
+
+	// $100 is 200 of the quote token, bought at 2000 and sold at 2100 for a
+	// profit of 10 tokens, or $5
+	opp := opps[0]
+	if opp.AmountIn.Cmp(FromUnits(200, usdc.Decimals)) != 0 {
+		t.Errorf("AmountIn = %v, want 200 %s", opp.AmountIn, usdc.Symbol)
+	}
+	if math.Abs(opp.ProfitUSD-5) > 1e-9 {
+		t.Errorf("ProfitUSD = %v, want 5", opp.ProfitUSD)
+	}
+	if math.Abs(opp.Profit.GrossProfitUSD-5) > 1e-6 {
+		t.Errorf("GrossProfitUSD = %v, want 5", opp.Profit.GrossProfitUSD)
+	}
+}
+
+func TestSpreadScannerWatchesBookUpdates(t *testing.T) {
+	scanner := NewSpreadScanner(nil, nil, nil, nil, nil, SpreadConfig{})
+	updates := make(chan cex.BookUpdate)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner.Watch(ctx, updates)
+	}()
+
+	eth := cex.Market{Base: "ETH", Quote: "USD"}
+	book := func(exchange constants.Exchange, bid float64, age time.Duration) cex.BookUpdate {
+		return cex.BookUpdate{
+			Exchange: exchange,
+			Market:   eth,
+			Book: &cex.OrderBook{
+				Exchange:  exchange,
+				Market:    eth,
+				Bids:      []cex.Level{{Price: bid, Size: 1}, {Price: bid - 1, Size: 1}},
+				Asks:      []cex.Level{{Price: bid + 1, Size: 1}, {Price: bid + 2, Size: 1}},
+				Timestamp: time.Now().Add(-age),
+			},
+			MaxAge: time.Minute,
+		}
+	}
+
+	updates <- book(constants.BINANCE, 100, 0)
+	updates <- book(constants.BINANCE, 101, 0)
+	updates <- book(constants.KRAKEN, 99, 0)
+	updates <- book(constants.GEMINI, 98, time.Hour)
+	// Kraken went out of sync after its book was sent
+	updates <- cex.BookUpdate{Exchange: constants.KRAKEN, Market: eth, MaxAge: time.Minute}
+	// The channel is unbuffered, so the repeated send returns only once the
+	// first one is stored
+	updates <- book(constants.BINANCE, 102, 0)
+	updates <- book(constants.BINANCE, 102, 0)
+
+	books := scanner.orderBooks(ctx, 1)
+	if len(books) != 1 {
+		t.Fatalf("scanning %d books, want only the fresh synced Binance book", len(books))
+	}
+	if books[0].Exchange != constants.BINANCE || books[0].Bids[0].Price != 102 {
+		t.Errorf("scanning %s book at %v, want the latest Binance book", books[0].Exchange, books[0].Bids[0].Price)
+	}
+	if len(books[0].Bids) != 1 || len(books[0].Asks) != 1 {
+		t.Errorf("scanning %d bids and %d asks, want the book truncated to 1 level", len(books[0].Bids), len(books[0].Asks))
+	}
+
+	cancel()
+	<-done
+}
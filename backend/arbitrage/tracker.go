@@ -0,0 +1,306 @@
+package arbitrage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/blockchain/connection"
+	"github.com/arbie-buckets/events"
+)
+
+// ErrDraining is returned by BeginSend once shutdown has started
+var ErrDraining = errors.New("shutting down, not accepting new trades")
+
+// PendingTrade is a submitted trade whose receipt has not been seen yet
+type PendingTrade struct {
+	TxHash      string    `json:"txHash"`
+	FromToken   string    `json:"fromToken"`
+	ToToken     string    `json:"toToken"`
+	AmountIn    string    `json:"amountIn"`
+	MinReturn   string    `json:"minReturn"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// TradeTracker follows executed trades from send until their receipt and
+// publishes each state change. On shutdown it refuses new sends, drains
+// the ones in flight and saves trades still waiting for a receipt so they
+// are followed again after a restart.
+type TradeTracker struct {
+	service *blockchain.BlockchainService
+	hub     *events.Hub
+	path    string
+
+	mutex    sync.Mutex
+	pending  map[string]PendingTrade
+	draining bool
+
+	// stopped is set once watching has ended; trades tracked afterwards
+	// are saved immediately instead of watched
+	stopped bool
+
+	sends   sync.WaitGroup
+	watches sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// NewTradeTracker creates a tracker. Trades saved at path by a previous
+// run are watched again; an empty path disables persistence.
+func NewTradeTracker(service *blockchain.BlockchainService, hub *events.Hub, path string) (*TradeTracker, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &TradeTracker{
+		service: service,
+		hub:     hub,
+		path:    path,
+		pending: make(map[string]PendingTrade),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	if err := t.load(); err != nil {
+		cancel()
+		return nil, err
+	}
+	t.mutex.Lock()
+	for _, trade := range t.pending {
+		log.Printf("Resuming receipt tracking for trade %s", trade.TxHash)
+		t.watch(trade)
+	}
+	t.mutex.Unlock()
+
+	return t, nil
+}
+
+// BeginSend registers a trade about to be sent and returns a function to
+// call once it has been sent or abandoned. It returns ErrDraining after
+// shutdown has started.
+func (t *TradeTracker) BeginSend() (func(), error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.draining {
+		return nil, ErrDraining
+	}
+	t.sends.Add(1)
+
+	var once sync.Once
+	return func() { once.Do(t.sends.Done) }, nil
+}
+
+// Track publishes a sent trade and watches it until it is mined. A trade
+// tracked after shutdown has stopped watching, because its send outlived
+// the drain, is saved for the next run instead.
+func (t *TradeTracker) Track(trade PendingTrade) {
+	if trade.SubmittedAt.IsZero() {
+		trade.SubmittedAt = time.Now()
+	}
+
+	events.PublishTrade(t.hub, trade.TxHash, events.TradeSubmitted, map[string]interface{}{
+		"fromToken": trade.FromToken,
+		"toToken":   trade.ToToken,
+		"amountIn":  trade.AmountIn,
+		"minReturn": trade.MinReturn,
+	})
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.pending[trade.TxHash] = trade
+	if !t.stopped {
+		t.watch(trade)
+		return
+	}
+	if err := t.save(); err != nil {
+		log.Printf("Warning: Failed to save trade %s sent during shutdown: %v", trade.TxHash, err)
+	}
+}
+
+// Pending returns the trades still waiting for a receipt, oldest first
+func (t *TradeTracker) Pending() []PendingTrade {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.pendingLocked()
+}
+
+// pendingLocked returns the pending trades, oldest first. The caller holds
+// the mutex.
+func (t *TradeTracker) pendingLocked() []PendingTrade {
+	trades := make([]PendingTrade, 0, len(t.pending))
+	for _, trade := range t.pending {
+		trades = append(trades, trade)
+	}
+	sort.Slice(trades, func(i, j int) bool { return trades[i].SubmittedAt.Before(trades[j].SubmittedAt) })
+	return trades
+}
+
+// Shutdown stops accepting sends, waits for sends in flight and then for
+// pending receipts until ctx is done, and saves the trades still pending.
+// Sends the deadline cuts off save their trades themselves once sent. It
+// returns ctx's error when the deadline cut the drain short.
+func (t *TradeTracker) Shutdown(ctx context.Context) error {
+	t.mutex.Lock()
+	t.draining = true
+	t.mutex.Unlock()
+
+	drainErr := wait(ctx, &t.sends)
+	if drainErr == nil {
+		drainErr = wait(ctx, &t.watches)
+	}
+
+	// Stop watching; no watch starts once stopped is set, so the wait
+	// below cannot race a new one
+	t.mutex.Lock()
+	t.stopped = true
+	t.mutex.Unlock()
+	t.cancel()
+	t.watches.Wait()
+
+	// Whatever is still pending is saved for the next run
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if err := t.save(); err != nil {
+		return fmt.Errorf("failed to save pending trades: %w", err)
+	}
+	return drainErr
+}
+
+// watch waits for a trade's receipt in the background and publishes
+// whether it succeeded. The trade is only settled once its receipt has
+// been seen: while the node cannot be reached, including before the
+// service has connected on startup, the check is retried with backoff.
+// The caller holds the mutex and has checked that watching has not
+// stopped.
+func (t *TradeTracker) watch(trade PendingTrade) {
+	if t.service == nil {
+		return
+	}
+
+	t.watches.Add(1)
+	go func() {
+		defer t.watches.Done()
+
+		backoff := t.service.NewBackoff()
+		for {
+			receipt, err := t.service.WaitForTransaction(t.ctx, common.HexToHash(trade.TxHash))
+			if t.ctx.Err() != nil {
+				// Interrupted by shutdown; the trade stays pending
+				return
+			}
+			if receipt != nil {
+				t.settle(trade, receipt, err)
+				return
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				// Not mined within the wait timeout, which does not mean it
+				// failed; it stays pending and is checked again on the next run
+				log.Printf("Warning: No receipt for trade %s within the wait timeout; it stays pending", trade.TxHash)
+				return
+			}
+
+			// The connection manager reports its own connection failures
+			delay := backoff.Next()
+			if !errors.Is(err, connection.ErrNotConnected) && !errors.Is(err, connection.ErrCircuitOpen) {
+				log.Printf("Warning: Failed to check trade %s, retrying in %s: %v", trade.TxHash, delay.Round(time.Millisecond), err)
+			}
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}()
+}
+
+// settle removes a mined trade from the pending trades and publishes
+// whether it succeeded
+func (t *TradeTracker) settle(trade PendingTrade, receipt *types.Receipt, err error) {
+	t.mutex.Lock()
+	delete(t.pending, trade.TxHash)
+	t.mutex.Unlock()
+
+	details := map[string]interface{}{
+		"blockNumber": receipt.BlockNumber.Uint64(),
+		"gasUsed":     receipt.GasUsed,
+	}
+	if err != nil {
+		details["error"] = err.Error()
+		events.PublishTrade(t.hub, trade.TxHash, events.TradeFailed, details)
+		return
+	}
+	events.PublishTrade(t.hub, trade.TxHash, events.TradeConfirmed, details)
+}
+
+// load reads trades saved by a previous run, if any
+func (t *TradeTracker) load() error {
+	if t.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read pending trades: %w", err)
+	}
+
+	var trades []PendingTrade
+	if err := json.Unmarshal(data, &trades); err != nil {
+		return fmt.Errorf("failed to decode pending trades: %w", err)
+	}
+	for _, trade := range trades {
+		t.pending[trade.TxHash] = trade
+	}
+	return nil
+}
+
+// save writes the pending trades to the configured path, replacing the
+// file atomically. The caller holds the mutex, which keeps saves from
+// interleaving.
+func (t *TradeTracker) save() error {
+	if t.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(t.pendingLocked())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.path)
+}
+
+// wait waits for group until ctx is done
+func wait(ctx context.Context, group *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		group.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
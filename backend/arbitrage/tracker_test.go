@@ -0,0 +1,176 @@
+package arbitrage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/blockchain/connection"
+	"github.com/arbie-buckets/blockchain/simulated/simtest"
+	"github.com/arbie-buckets/events"
+)
+
+// readSaved returns the trades saved at path
+func readSaved(t *testing.T, path string) []PendingTrade {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trades []PendingTrade
+	if err := json.Unmarshal(data, &trades); err != nil {
+		t.Fatal(err)
+	}
+	return trades
+}
+
+// tradeStates returns the trade states delivered to sub so far
+func tradeStates(sub *events.Subscription) []string {
+	var states []string
+	for {
+		select {
+		case event := <-sub.Events():
+			states = append(states, event.Data.(map[string]interface{})["state"].(string))
+		default:
+			return states
+		}
+	}
+}
+
+func TestTrackAfterShutdownSavesTrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.json")
+	tracker, err := NewTradeTracker(nil, events.NewHub(16), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A send that outlives the drain deadline
+	done, err := tracker.BeginSend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tracker.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Shutdown() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := tracker.BeginSend(); err != ErrDraining {
+		t.Fatalf("BeginSend() after shutdown = %v, want %v", err, ErrDraining)
+	}
+
+	tracker.Track(PendingTrade{TxHash: "0x01"})
+	done()
+
+	saved := readSaved(t, path)
+	if len(saved) != 1 || saved[0].TxHash != "0x01" {
+		t.Fatalf("saved %v, want the late trade", saved)
+	}
+}
+
+func TestWatchTimeoutKeepsTradePending(t *testing.T) {
+	service := simtest.New(t, simtest.Options{Timeouts: blockchain.Timeouts{Wait: 200 * time.Millisecond}}).Service
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Sent but never mined: no block is committed
+	usdc, _ := blockchain.FindToken("USDC")
+	txHash, err := service.ExecuteArbitrage(ctx, usdc.HexAddress(), usdc.HexAddress(), big.NewInt(1), big.NewInt(0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hub := events.NewHub(16)
+	sub, _, _ := hub.Subscribe([]string{events.TopicTrades}, 0, 16)
+	defer sub.Close()
+	path := filepath.Join(t.TempDir(), "pending.json")
+	tracker, err := NewTradeTracker(service, hub, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker.Track(PendingTrade{TxHash: txHash})
+
+	// The watch gives up after the wait timeout without failing the trade
+	if err := tracker.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	if states := tradeStates(sub); len(states) != 1 || states[0] != events.TradeSubmitted {
+		t.Errorf("published states %v, want only %s", states, events.TradeSubmitted)
+	}
+	saved := readSaved(t, path)
+	if len(saved) != 1 || saved[0].TxHash != txHash {
+		t.Errorf("saved %v, want the unmined trade", saved)
+	}
+}
+
+func TestResumeWaitsForConnection(t *testing.T) {
+	chain := simtest.New(t, simtest.Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// A trade mined while the previous run was down
+	usdc, _ := blockchain.FindToken("USDC")
+	txHash, err := chain.Service.ExecuteArbitrage(ctx, usdc.HexAddress(), usdc.HexAddress(), big.NewInt(1), big.NewInt(0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Backend.Commit()
+
+	path := filepath.Join(t.TempDir(), "pending.json")
+	data, err := json.Marshal([]PendingTrade{{TxHash: txHash}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The restarted service cannot reach the node yet
+	var reachable atomic.Bool
+	service := chain.NewService(t, func(ctx context.Context, rpcURL string) (connection.ChainClient, error) {
+		if !reachable.Load() {
+			return nil, errors.New("node unreachable")
+		}
+		return chain.Backend.Dial(ctx, rpcURL)
+	})
+
+	hub := events.NewHub(16)
+	sub, _, _ := hub.Subscribe([]string{events.TopicTrades}, 0, 16)
+	defer sub.Close()
+	tracker, err := NewTradeTracker(service, hub, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if states := tradeStates(sub); len(states) != 0 {
+		t.Fatalf("published %v while the node was unreachable, want nothing", states)
+	}
+	if pending := tracker.Pending(); len(pending) != 1 {
+		t.Fatalf("%d trades pending while the node was unreachable, want the resumed one", len(pending))
+	}
+
+	// Once connected the receipt settles the trade
+	reachable.Store(true)
+	select {
+	case event := <-sub.Events():
+		if state := event.Data.(map[string]interface{})["state"]; state != events.TradeConfirmed {
+			t.Errorf("published %v, want %s", state, events.TradeConfirmed)
+		}
+	case <-ctx.Done():
+		t.Fatal("trade was not settled after the node became reachable")
+	}
+
+	if err := tracker.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	if saved := readSaved(t, path); len(saved) != 0 {
+		t.Errorf("saved %v, want no pending trades", saved)
+	}
+}
@@ -0,0 +1,361 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultChainlinkGrace is allowed on top of a feed's heartbeat before its
+// answer is considered stale
+const DefaultChainlinkGrace = 2 * time.Minute
+
+var (
+	// ErrNoFeed is returned when no feed is registered for a token
+	ErrNoFeed = errors.New("no chainlink feed for token")
+
+	// ErrStaleRound is returned when a feed has not updated within its
+	// heartbeat
+	ErrStaleRound = errors.New("chainlink round is stale")
+
+	// ErrInvalidRound is returned when a round is incomplete, carried over
+	// from an earlier round or has a non-positive answer
+	ErrInvalidRound = errors.New("chainlink round is invalid")
+)
+
+// aggregatorV3ABI covers the AggregatorV3Interface methods used to read feeds
+const aggregatorV3ABI = `[
+    {
+        "inputs": [],
+        "name": "decimals",
+        "outputs": [{"internalType": "uint8", "name": "", "type": "uint8"}],
+        "stateMutability": "view",
+        "type": "function"
+    },
+    {
+        "inputs": [],
+        "name": "latestRoundData",
+        "outputs": [
+            {"internalType": "uint80", "name": "roundId", "type": "uint80"},
+            {"internalType": "int256", "name": "answer", "type": "int256"},
+            {"internalType": "uint256", "name": "startedAt", "type": "uint256"},
+            {"internalType": "uint256", "name": "updatedAt", "type": "uint256"},
+            {"internalType": "uint80", "name": "answeredInRound", "type": "uint80"}
+        ],
+        "stateMutability": "view",
+        "type": "function"
+    }
+]`
+
+// ChainlinkFeed is a USD price feed for a token
+type ChainlinkFeed struct {
+	Symbol  string
+	Address common.Address
+
+	// Heartbeat is the longest the feed goes without an update
+	Heartbeat time.Duration
+}
+
+// baseChainlinkFeeds are the Base mainnet USD feeds of the default tokens
+var baseChainlinkFeeds = []ChainlinkFeed{
+	{Symbol: "ETH", Address: common.HexToAddress("0x71041dddad3595F9CEd3DcCFBe3D1F4b0a16Bb70"), Heartbeat: 20 * time.Minute},
+	{Symbol: "USDC", Address: common.HexToAddress("0x7e860098F58bBFC8648a4311b374B1D669a2bc6B"), Heartbeat: 24 * time.Hour},
+}
+
+// ChainlinkRegistry maps token symbols to their feeds
+type ChainlinkRegistry struct {
+	mutex sync.RWMutex
+	feeds map[string]ChainlinkFeed
+}
+
+// NewChainlinkRegistry creates a registry containing feeds
+func NewChainlinkRegistry(feeds ...ChainlinkFeed) *ChainlinkRegistry {
+	registry := &ChainlinkRegistry{feeds: make(map[string]ChainlinkFeed)}
+	for _, feed := range feeds {
+		registry.Register(feed)
+	}
+	return registry
+}
+
+// Register adds or replaces the feed for its symbol
+func (r *ChainlinkRegistry) Register(feed ChainlinkFeed) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.feeds[strings.ToUpper(feed.Symbol)] = feed
+}
+
+// Feed returns the feed for a token symbol
+func (r *ChainlinkRegistry) Feed(symbol string) (ChainlinkFeed, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	feed, ok := r.feeds[strings.ToUpper(symbol)]
+	return feed, ok
+}
+
+// Feeds returns every registered feed
+func (r *ChainlinkRegistry) Feeds() []ChainlinkFeed {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	feeds := make([]ChainlinkFeed, 0, len(r.feeds))
+	for _, feed := range r.feeds {
+		feeds = append(feeds, feed)
+	}
+	return feeds
+}
+
+// ParseChainlinkFeeds parses a comma separated list of SYMBOL=ADDRESS:HEARTBEAT
+// entries, e.g. "ETH=0x7104...Bb70:20m". The heartbeat defaults to one hour.
+func ParseChainlinkFeeds(value string) ([]ChainlinkFeed, error) {
+	var feeds []ChainlinkFeed
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		symbol, rest, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid chainlink feed %q", entry)
+		}
+		address, heartbeat, _ := strings.Cut(rest, ":")
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid chainlink feed address %q", address)
+		}
+
+		feed := ChainlinkFeed{
+			Symbol:    strings.ToUpper(strings.TrimSpace(symbol)),
+			Address:   common.HexToAddress(address),
+			Heartbeat: time.Hour,
+		}
+		if heartbeat != "" {
+			duration, err := time.ParseDuration(heartbeat)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chainlink heartbeat %q: %w", heartbeat, err)
+			}
+			feed.Heartbeat = duration
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds, nil
+}
+
+// ChainlinkRound is the latest round of a feed
+type ChainlinkRound struct {
+	Feed            ChainlinkFeed
+	RoundID         *big.Int
+	Answer          *big.Int
+	Decimals        uint8
+	StartedAt       time.Time
+	UpdatedAt       time.Time
+	AnsweredInRound *big.Int
+}
+
+// Price returns the answer scaled by the feed's decimals
+func (r *ChainlinkRound) Price() float64 {
+	answer, _ := new(big.Float).SetInt(r.Answer).Float64()
+	return answer / math.Pow10(int(r.Decimals))
+}
+
+// ChainlinkReader reads AggregatorV3Interface feeds, batching calls through
+// the connection manager's RPC client
+type ChainlinkReader struct {
+	service  *BlockchainService
+	registry *ChainlinkRegistry
+	abi      abi.ABI
+	grace    time.Duration
+
+	// decimals never change, so they are read once per feed
+	mutex    sync.RWMutex
+	decimals map[common.Address]uint8
+}
+
+// NewChainlinkReader creates a reader for the feeds in registry
+func NewChainlinkReader(service *BlockchainService, registry *ChainlinkRegistry, grace time.Duration) (*ChainlinkReader, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(aggregatorV3ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse aggregator ABI: %w", err)
+	}
+	if grace < 0 {
+		grace = DefaultChainlinkGrace
+	}
+
+	return &ChainlinkReader{
+		service:  service,
+		registry: registry,
+		abi:      parsedABI,
+		grace:    grace,
+		decimals: make(map[common.Address]uint8),
+	}, nil
+}
+
+// Registry returns the feed registry
+func (r *ChainlinkReader) Registry() *ChainlinkRegistry {
+	return r.registry
+}
+
+// LatestRound returns the validated latest round of the token's feed
+func (r *ChainlinkReader) LatestRound(ctx context.Context, symbol string) (*ChainlinkRound, error) {
+	feed, ok := r.registry.Feed(symbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoFeed, symbol)
+	}
+
+	rounds, errs := r.LatestRounds(ctx, []ChainlinkFeed{feed})
+	return rounds[0], errs[0]
+}
+
+// LatestRounds reads the latest round of every feed in a single batch.
+// rounds[i] is nil whenever errs[i] is set.
+func (r *ChainlinkReader) LatestRounds(ctx context.Context, feeds []ChainlinkFeed) ([]*ChainlinkRound, []error) {
+	rounds := make([]*ChainlinkRound, len(feeds))
+	errs := make([]error, len(feeds))
+	fail := func(err error) ([]*ChainlinkRound, []error) {
+		for i := range errs {
+			errs[i] = err
+		}
+		return rounds, errs
+	}
+
+	roundData, err := r.abi.Pack("latestRoundData")
+	if err != nil {
+		return fail(fmt.Errorf("failed to pack latestRoundData call: %w", err))
+	}
+	decimalsData, err := r.abi.Pack("decimals")
+	if err != nil {
+		return fail(fmt.Errorf("failed to pack decimals call: %w", err))
+	}
+
+	// One latestRoundData call per feed, plus decimals for feeds not yet seen
+	var (
+		batch          []rpc.BatchElem
+		roundResults   = make([]hexutil.Bytes, len(feeds))
+		decimalResults = make(map[int]*hexutil.Bytes)
+	)
+	for i, feed := range feeds {
+		batch = append(batch, ethCall(feed.Address, roundData, &roundResults[i]))
+		if _, ok := r.cachedDecimals(feed.Address); !ok {
+			result := new(hexutil.Bytes)
+			decimalResults[i] = result
+			batch = append(batch, ethCall(feed.Address, decimalsData, result))
+		}
+	}
+
+	if err := r.service.BatchCall(ctx, batch); err != nil {
+		return fail(fmt.Errorf("failed to call chainlink feeds: %w", err))
+	}
+
+	// Batch elements are in feed order, decimals following their round call
+	next := 0
+	for i, feed := range feeds {
+		roundErr := batch[next].Error
+		next++
+
+		var decimalsErr error
+		if result, ok := decimalResults[i]; ok {
+			decimalsErr = batch[next].Error
+			next++
+			if decimalsErr == nil {
+				decimalsErr = r.storeDecimals(feed.Address, *result)
+			}
+		}
+
+		switch {
+		case roundErr != nil:
+			errs[i] = fmt.Errorf("failed to read %s feed: %w", feed.Symbol, roundErr)
+		case decimalsErr != nil:
+			errs[i] = fmt.Errorf("failed to read %s feed decimals: %w", feed.Symbol, decimalsErr)
+		default:
+			rounds[i], errs[i] = r.decodeRound(feed, roundResults[i])
+		}
+	}
+
+	return rounds, errs
+}
+
+// decodeRound unpacks latestRoundData output and validates the round
+func (r *ChainlinkReader) decodeRound(feed ChainlinkFeed, output []byte) (*ChainlinkRound, error) {
+	out, err := r.abi.Unpack("latestRoundData", output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s round: %w", feed.Symbol, err)
+	}
+
+	decimals, _ := r.cachedDecimals(feed.Address)
+	round := &ChainlinkRound{
+		Feed:            feed,
+		RoundID:         out[0].(*big.Int),
+		Answer:          out[1].(*big.Int),
+		Decimals:        decimals,
+		StartedAt:       time.Unix(out[2].(*big.Int).Int64(), 0),
+		UpdatedAt:       time.Unix(out[3].(*big.Int).Int64(), 0),
+		AnsweredInRound: out[4].(*big.Int),
+	}
+
+	if err := r.validate(round); err != nil {
+		return nil, err
+	}
+	return round, nil
+}
+
+// validate rejects incomplete, carried-over, non-positive and stale rounds
+func (r *ChainlinkReader) validate(round *ChainlinkRound) error {
+	symbol := round.Feed.Symbol
+	if round.Answer.Sign() <= 0 {
+		return fmt.Errorf("%w: %s answer %s is not positive", ErrInvalidRound, symbol, round.Answer)
+	}
+	if round.UpdatedAt.Unix() == 0 {
+		return fmt.Errorf("%w: %s round %s is incomplete", ErrInvalidRound, symbol, round.RoundID)
+	}
+	if round.AnsweredInRound.Cmp(round.RoundID) < 0 {
+		return fmt.Errorf("%w: %s round %s was answered in earlier round %s",
+			ErrInvalidRound, symbol, round.RoundID, round.AnsweredInRound)
+	}
+
+	age := time.Since(round.UpdatedAt)
+	if age > round.Feed.Heartbeat+r.grace {
+		return fmt.Errorf("%w: %s last updated %s ago (heartbeat %s)",
+			ErrStaleRound, symbol, age.Truncate(time.Second), round.Feed.Heartbeat)
+	}
+	return nil
+}
+
+func (r *ChainlinkReader) cachedDecimals(feed common.Address) (uint8, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	decimals, ok := r.decimals[feed]
+	return decimals, ok
+}
+
+func (r *ChainlinkReader) storeDecimals(feed common.Address, output []byte) error {
+	out, err := r.abi.Unpack("decimals", output)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.decimals[feed] = out[0].(uint8)
+	return nil
+}
+
+// ethCall builds a batched eth_call against the latest block
+func ethCall(to common.Address, data []byte, result *hexutil.Bytes) rpc.BatchElem {
+	return rpc.BatchElem{
+		Method: "eth_call",
+		Args: []interface{}{
+			map[string]interface{}{"to": to, "data": hexutil.Bytes(data)},
+			"latest",
+		},
+		Result: result,
+	}
+}
@@ -0,0 +1,182 @@
+package blockchain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Well-known contract addresses shared by several chains
+var (
+	// WETHPredeployAddress is the wrapped ether predeploy on OP Stack chains
+	WETHPredeployAddress = common.HexToAddress("0x4200000000000000000000000000000000000006")
+
+	// Multicall3Address is Multicall3's deterministic deployment address
+	Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+	// GasPriceOracleAddress is the OP Stack GasPriceOracle predeploy
+	GasPriceOracleAddress = common.HexToAddress("0x420000000000000000000000000000000000000F")
+)
+
+// Currency describes a chain's native currency
+type Currency struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// Ether is the native currency of Ethereum and its rollups
+var Ether = Currency{Name: "Ether", Symbol: "ETH", Decimals: 18}
+
+// ChainContracts are well-known contracts on a chain. A zero address means
+// the contract is not deployed there.
+type ChainContracts struct {
+	WETH       common.Address `json:"weth"`
+	Multicall3 common.Address `json:"multicall3"`
+
+	// GasPriceOracle prices the L1 data fee on OP Stack chains
+	GasPriceOracle common.Address `json:"gasPriceOracle"`
+
+	// DEXFactories are the pool factories of the DEXes deployed on the
+	// chain, keyed by DEX
+	DEXFactories map[string]common.Address `json:"dexFactories,omitempty"`
+}
+
+// ChainInfo describes a chain the backend can run against
+type ChainInfo struct {
+	ChainID        uint64         `json:"chainId"`
+	Name           string         `json:"name"`
+	NativeCurrency Currency       `json:"nativeCurrency"`
+	ExplorerURL    string         `json:"explorerUrl,omitempty"`
+	Contracts      ChainContracts `json:"contracts"`
+	Testnet        bool           `json:"testnet"`
+
+	// ChainlinkFeeds are the USD price feeds of the default tokens, empty
+	// where none are known
+	ChainlinkFeeds []ChainlinkFeed `json:"-"`
+}
+
+// TxURL returns the explorer page for a transaction, or "" without an
+// explorer
+func (c ChainInfo) TxURL(txHash string) string {
+	if c.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(c.ExplorerURL, "/") + "/tx/" + txHash
+}
+
+// AddressURL returns the explorer page for an address, or "" without an
+// explorer
+func (c ChainInfo) AddressURL(address string) string {
+	if c.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(c.ExplorerURL, "/") + "/address/" + address
+}
+
+// HasL1Fee reports whether transactions also pay an L1 data fee
+func (c ChainInfo) HasL1Fee() bool {
+	return c.Contracts.GasPriceOracle != (common.Address{})
+}
+
+// opStackContracts are the contracts at the same address on every OP Stack
+// chain
+var opStackContracts = ChainContracts{
+	WETH:           WETHPredeployAddress,
+	Multicall3:     Multicall3Address,
+	GasPriceOracle: GasPriceOracleAddress,
+}
+
+// baseContracts are the Base mainnet contracts, the OP Stack predeploys
+// plus the DEX deployments
+var baseContracts = ChainContracts{
+	WETH:           WETHPredeployAddress,
+	Multicall3:     Multicall3Address,
+	GasPriceOracle: GasPriceOracleAddress,
+	DEXFactories: map[string]common.Address{
+		"uniswap-v2": common.HexToAddress("0x8909Dc15e40173Ff4699343b6eB8132c65e18eC6"),
+		"sushiswap":  common.HexToAddress("0x71524B4f93c58fcbF659783284E38825f0622859"),
+		"alienbase":  common.HexToAddress("0x3E84D913803b02A4a7f027165E8cA42C14C0FdE7"),
+		"aerodrome":  common.HexToAddress("0x420DD381b31aEf6683db6B902084cB0FFECe40Da"),
+	},
+}
+
+// chains is the chain registry, keyed by chain ID
+var (
+	chainsMutex sync.RWMutex
+	chains      = map[uint64]ChainInfo{
+		8453: {
+			ChainID:        8453,
+			Name:           "Base",
+			NativeCurrency: Ether,
+			ExplorerURL:    "https://basescan.org",
+			Contracts:      baseContracts,
+			ChainlinkFeeds: baseChainlinkFeeds,
+		},
+		84532: {
+			ChainID:        84532,
+			Name:           "Base Sepolia",
+			NativeCurrency: Ether,
+			ExplorerURL:    "https://sepolia.basescan.org",
+			Contracts:      opStackContracts,
+			Testnet:        true,
+		},
+		31337: {
+			ChainID:        31337,
+			Name:           "Anvil",
+			NativeCurrency: Ether,
+			Testnet:        true,
+		},
+		1337: {
+			ChainID:        1337,
+			Name:           "Simulated",
+			NativeCurrency: Ether,
+			Testnet:        true,
+		},
+	}
+)
+
+// RegisterChain adds a chain to the registry or replaces the entry with the
+// same chain ID
+func RegisterChain(info ChainInfo) {
+	chainsMutex.Lock()
+	defer chainsMutex.Unlock()
+	chains[info.ChainID] = info
+}
+
+// LookupChain returns the registered chain with the given ID
+func LookupChain(chainID uint64) (ChainInfo, bool) {
+	chainsMutex.RLock()
+	defer chainsMutex.RUnlock()
+	info, ok := chains[chainID]
+	return info, ok
+}
+
+// ChainFor returns the registered chain with the given ID, or a generic
+// entry naming it by ID with ether as its currency and no known contracts
+func ChainFor(chainID uint64) ChainInfo {
+	if info, ok := LookupChain(chainID); ok {
+		return info
+	}
+	name := "Unknown chain"
+	if chainID != 0 {
+		name = fmt.Sprintf("Chain %d", chainID)
+	}
+	return ChainInfo{ChainID: chainID, Name: name, NativeCurrency: Ether}
+}
+
+// Chains returns every registered chain by chain ID
+func Chains() []ChainInfo {
+	chainsMutex.RLock()
+	defer chainsMutex.RUnlock()
+
+	list := make([]ChainInfo, 0, len(chains))
+	for _, info := range chains {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ChainID < list[j].ChainID })
+	return list
+}
@@ -0,0 +1,255 @@
+package connection
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInitialBackoff is the delay before the first reconnect attempt
+	DefaultInitialBackoff = time.Second
+
+	// DefaultMaxBackoff caps the delay between reconnect attempts
+	DefaultMaxBackoff = time.Minute
+
+	// DefaultBackoffMultiplier grows the delay after each failed attempt
+	DefaultBackoffMultiplier = 2.0
+
+	// DefaultBackoffJitter randomizes each delay by up to this fraction so
+	// that restarted instances do not retry in lockstep
+	DefaultBackoffJitter = 0.2
+
+	// DefaultFailureThreshold is the number of consecutive failed connection
+	// attempts that opens the circuit
+	DefaultFailureThreshold = 3
+
+	// DefaultBreakerCooldown is how long the circuit stays open before a
+	// half-open probe is allowed
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without attempting a connection while the
+// circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+// String returns a string representation of the breaker state
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "Closed"
+	case BreakerOpen:
+		return "Open"
+	case BreakerHalfOpen:
+		return "HalfOpen"
+	default:
+		return "Unknown"
+	}
+}
+
+// Config controls reconnect backoff and circuit breaking
+type Config struct {
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	BackoffJitter     float64
+
+	FailureThreshold int
+	BreakerCooldown  time.Duration
+
+	// Dial opens connections; DialRPC unless replaced, for example by a
+	// simulated backend
+	Dial Dialer
+}
+
+// DefaultConfig returns the default backoff and breaker settings
+func DefaultConfig() Config {
+	return Config{
+		InitialBackoff:    DefaultInitialBackoff,
+		MaxBackoff:        DefaultMaxBackoff,
+		BackoffMultiplier: DefaultBackoffMultiplier,
+		BackoffJitter:     DefaultBackoffJitter,
+		FailureThreshold:  DefaultFailureThreshold,
+		BreakerCooldown:   DefaultBreakerCooldown,
+		Dial:              DialRPC,
+	}
+}
+
+// withDefaults fills unset fields from DefaultConfig
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = defaults.InitialBackoff
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		c.MaxBackoff = max(defaults.MaxBackoff, c.InitialBackoff)
+	}
+	if c.BackoffMultiplier < 1 {
+		c.BackoffMultiplier = defaults.BackoffMultiplier
+	}
+	if c.BackoffJitter < 0 || c.BackoffJitter > 1 {
+		c.BackoffJitter = defaults.BackoffJitter
+	}
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = defaults.FailureThreshold
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = defaults.BreakerCooldown
+	}
+	if c.Dial == nil {
+		c.Dial = defaults.Dial
+	}
+	return c
+}
+
+// Backoff produces exponentially growing, jittered delays
+type Backoff struct {
+	config  Config
+	attempt int
+}
+
+// NewBackoff creates a backoff starting at config.InitialBackoff
+func NewBackoff(config Config) *Backoff {
+	return &Backoff{config: config.withDefaults()}
+}
+
+// Next returns the delay before the next attempt and advances the backoff
+func (b *Backoff) Next() time.Duration {
+	delay := float64(b.config.InitialBackoff)
+	for i := 0; i < b.attempt && delay < float64(b.config.MaxBackoff); i++ {
+		delay *= b.config.BackoffMultiplier
+	}
+	delay = min(delay, float64(b.config.MaxBackoff))
+	b.attempt++
+
+	// Spread the delay evenly within ±jitter
+	delay *= 1 + b.config.BackoffJitter*(2*rand.Float64()-1)
+	return time.Duration(delay)
+}
+
+// Reset starts the backoff over from the initial delay
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Breaker is a circuit breaker over connection attempts. It opens after
+// FailureThreshold consecutive failures, fails fast for BreakerCooldown and
+// then lets a single half-open probe through: success closes it again and
+// failure reopens it.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mutex    sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker creates a closed breaker
+func NewBreaker(config Config) *Breaker {
+	config = config.withDefaults()
+	return &Breaker{
+		threshold: config.FailureThreshold,
+		cooldown:  config.BreakerCooldown,
+	}
+}
+
+// SetLimits changes the failure threshold and cooldown, keeping the
+// breaker's state
+func (b *Breaker) SetLimits(config Config) {
+	config = config.withDefaults()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.threshold = config.FailureThreshold
+	b.cooldown = config.BreakerCooldown
+}
+
+// Allow returns nil if a connection attempt may be made now. Once the
+// cooldown has passed the first caller becomes the half-open probe and the
+// rest keep getting ErrCircuitOpen until it reports back.
+func (b *Breaker) Allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Release gives back a half-open probe whose attempt ended without an
+// outcome, such as one abandoned because the endpoint changed, so that the
+// next caller may probe instead
+func (b *Breaker) Release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.probing = false
+}
+
+// Success records a successful attempt and closes the breaker
+func (b *Breaker) Success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a failed attempt, opening the breaker when the threshold
+// is reached or the half-open probe failed
+func (b *Breaker) Failure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State returns the breaker's current state
+func (b *Breaker) State() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+// Remaining returns how long until an open breaker allows a probe
+func (b *Breaker) Remaining() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state != BreakerOpen {
+		return 0
+	}
+	return max(0, b.cooldown-time.Since(b.openedAt))
+}
@@ -0,0 +1,57 @@
+package connection
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ChainClient is the part of the go-ethereum client API the service uses.
+// *ethclient.Client connections and simulated backends both implement it.
+type ChainClient interface {
+	ethereum.BlockNumberReader
+	ethereum.ChainIDReader
+	ethereum.ChainReader
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+	ethereum.GasPricer
+	ethereum.LogFilterer
+	ethereum.PendingStateReader
+	ethereum.TransactionReader
+	ethereum.TransactionSender
+
+	// NetworkID returns the network ID used to sign transactions
+	NetworkID(ctx context.Context) (*big.Int, error)
+
+	// BatchCallContext sends several JSON-RPC calls in one request
+	BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error
+
+	// Close releases the connection
+	Close()
+}
+
+// Dialer opens a ChainClient for an RPC URL
+type Dialer func(ctx context.Context, rpcURL string) (ChainClient, error)
+
+// DialRPC connects to a JSON-RPC endpoint over HTTP, WebSocket or IPC
+func DialRPC(ctx context.Context, rpcURL string) (ChainClient, error) {
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcClient{Client: client}, nil
+}
+
+// rpcClient adds batching to ethclient.Client
+type rpcClient struct {
+	*ethclient.Client
+}
+
+// BatchCallContext sends the batch through the underlying RPC client
+func (c *rpcClient) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	return c.Client.Client().BatchCallContext(ctx, batch)
+}
@@ -5,23 +5,35 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math/big"
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/arbie-buckets/config"
 )
 
 const (
-	// ReconnectInterval defines how often to attempt reconnection
-	ReconnectInterval = 30 * time.Second
-
 	// HealthCheckInterval defines how often to check connection health
 	HealthCheckInterval = 1 * time.Minute
 
 	// ConnectionTimeout defines timeout for connection operations
 	ConnectionTimeout = 10 * time.Second
+
+	// TransitionHistorySize is how many recent status transitions are kept
+	TransitionHistorySize = 50
 )
 
+// ErrConnectInProgress is returned by Connect while another attempt is
+// still dialing
+var ErrConnectInProgress = errors.New("connection attempt already in progress")
+
+// ErrClosed is returned once the manager has been closed
+var ErrClosed = errors.New("connection manager closed")
+
+// ErrNotConnected is returned by Client while the background loop has not
+// (re)connected yet
+var ErrNotConnected = errors.New("not connected")
+
 // ConnectionStatus represents the current state of the blockchain connection
 type ConnectionStatus int
 
@@ -48,38 +60,92 @@ func (s ConnectionStatus) String() string {
 	}
 }
 
+// Transition is a change of connection status
+type Transition struct {
+	From   ConnectionStatus
+	To     ConnectionStatus
+	Err    error
+	Reason string
+	At     time.Time
+}
+
+// ConnectionStats summarizes the connection's history
+type ConnectionStats struct {
+	// ConnectedSince is when the current connection was established, zero
+	// while disconnected
+	ConnectedSince time.Time
+
+	// LastDisconnect is the most recent transition away from connected
+	LastDisconnect *Transition
+
+	// Reconnects counts successful connections after the first
+	Reconnects int
+}
+
+// Uptime returns how long the current connection has been up
+func (s ConnectionStats) Uptime() time.Duration {
+	if s.ConnectedSince.IsZero() {
+		return 0
+	}
+	return time.Since(s.ConnectedSince)
+}
+
 // ConnectionManager handles blockchain connection with resilience features
 type ConnectionManager struct {
-	client         *ethclient.Client
-	url            string
-	mutex          sync.RWMutex
-	status         ConnectionStatus
-	lastError      error
-	stopChan       chan struct{}
-	reconnectChan  chan struct{}
-	isReconnecting bool
+	client        ChainClient
+	url           string
+	mutex         sync.RWMutex
+	status        ConnectionStatus
+	lastError     error
+	stopChan      chan struct{}
+	reconnectChan chan struct{}
+	loopOnce      sync.Once
+	closeOnce     sync.Once
+	closed        bool
+
+	config  Config
+	breaker *Breaker
+
+	transitions    []Transition
+	subscribers    map[*subscriber]struct{}
+	connectedSince time.Time
+	lastDisconnect *Transition
+	connects       int
 }
 
-// NewConnectionManager creates a new blockchain connection manager
-func NewConnectionManager(rpcURL string) *ConnectionManager {
-	if rpcURL == "" {
-		rpcURL = "https://sepolia.base.org" // Default to Base Sepolia testnet
-	}
+// NewConnectionManager creates a new blockchain connection manager, filling
+// unset config fields from DefaultConfig. The chain's endpoint comes from
+// the caller; there is no default network.
+func NewConnectionManager(rpcURL string, config Config) *ConnectionManager {
+	config = config.withDefaults()
 
 	return &ConnectionManager{
 		url:           rpcURL,
 		status:        StatusDisconnected,
 		stopChan:      make(chan struct{}),
 		reconnectChan: make(chan struct{}, 1),
+		config:        config,
+		breaker:       NewBreaker(config),
+		subscribers:   make(map[*subscriber]struct{}),
 	}
 }
 
-// Connect establishes a connection to the blockchain
+// Connect establishes a connection to the blockchain, failing fast with
+// ErrCircuitOpen while the circuit breaker is open. Every attempt the
+// breaker allowed is settled with it, including abandoned ones. The first
+// call starts the background health check and reconnect loop, whether or
+// not it succeeds.
 func (cm *ConnectionManager) Connect() error {
+	defer cm.start()
+
 	cm.mutex.Lock()
+	if cm.closed {
+		cm.mutex.Unlock()
+		return ErrClosed
+	}
 	if cm.status == StatusConnecting {
 		cm.mutex.Unlock()
-		return errors.New("connection attempt already in progress")
+		return ErrConnectInProgress
 	}
 
 	if cm.client != nil {
@@ -87,7 +153,13 @@ func (cm *ConnectionManager) Connect() error {
 		return nil // Already connected
 	}
 
-	cm.status = StatusConnecting
+	// Only an attempt that will dial may take the breaker's probe
+	if err := cm.breaker.Allow(); err != nil {
+		cm.mutex.Unlock()
+		return err
+	}
+	cm.setStatus(StatusConnecting, nil, "connecting")
+	url, dial := cm.url, cm.config.Dial
 	cm.mutex.Unlock()
 
 	// Create a context with timeout for the connection
@@ -95,13 +167,13 @@ func (cm *ConnectionManager) Connect() error {
 	defer cancel()
 
 	// Connect to blockchain
-	client, err := ethclient.DialContext(ctx, cm.url)
+	client, err := dial(ctx, url)
 	if err != nil {
 		cm.mutex.Lock()
-		cm.status = StatusError
-		cm.lastError = err
+		cm.setStatus(StatusError, err, "dial failed")
 		cm.mutex.Unlock()
-		return fmt.Errorf("failed to connect to blockchain at %s: %w", cm.url, err)
+		cm.breaker.Failure()
+		return fmt.Errorf("failed to connect to blockchain at %s: %w", config.RedactURL(url), err)
 	}
 
 	// Verify connection by getting network ID
@@ -109,48 +181,64 @@ func (cm *ConnectionManager) Connect() error {
 	if err != nil {
 		client.Close()
 		cm.mutex.Lock()
-		cm.status = StatusError
-		cm.lastError = err
+		cm.setStatus(StatusError, err, "network ID check failed")
 		cm.mutex.Unlock()
+		cm.breaker.Failure()
 		return fmt.Errorf("failed to get network ID: %w", err)
 	}
 
 	// Set the client if everything is successful
 	cm.mutex.Lock()
+	if cm.closed {
+		// Closed while dialing
+		cm.breaker.Release()
+		cm.mutex.Unlock()
+		client.Close()
+		return ErrClosed
+	}
+	if cm.url != url {
+		// Reconfigured while dialing; the loop connects to the new endpoint
+		cm.setStatus(StatusDisconnected, nil, "endpoint changed")
+		cm.breaker.Release()
+		cm.mutex.Unlock()
+		client.Close()
+		cm.TriggerReconnect()
+		return fmt.Errorf("endpoint changed while connecting to %s", config.RedactURL(url))
+	}
 	cm.client = client
-	cm.status = StatusConnected
-	cm.lastError = nil
-	cm.mutex.Unlock()
-
-	// Start health check routine when connecting for the first time
-	if cm.stopChan != nil {
-		go cm.startHealthCheck()
+	reason := "connected"
+	if cm.connects > 0 {
+		reason = "reconnected"
 	}
+	cm.setStatus(StatusConnected, nil, reason)
+	cm.mutex.Unlock()
+	cm.breaker.Success()
 
-	log.Printf("Connected to blockchain at %s (Network ID: %s)", cm.url, networkID.String())
+	log.Printf("Connected to blockchain at %s (Network ID: %s)", config.RedactURL(url), networkID.String())
 	return nil
 }
 
-// Client returns the ethclient.Client instance, creating a connection if needed
-func (cm *ConnectionManager) Client() (*ethclient.Client, error) {
+// Client returns the connected chain client. It never dials: while
+// disconnected it fails fast and asks the background loop to reconnect,
+// so callers on the request path never wait out a connection timeout.
+func (cm *ConnectionManager) Client() (ChainClient, error) {
 	cm.mutex.RLock()
-	if cm.client != nil && cm.status == StatusConnected {
-		client := cm.client
-		cm.mutex.RUnlock()
-		return client, nil
-	}
+	client, status, closed := cm.client, cm.status, cm.closed
 	cm.mutex.RUnlock()
 
-	// If not connected, try to connect
-	if err := cm.Connect(); err != nil {
-		// If connection fails, trigger reconnect attempt
-		cm.TriggerReconnect()
-		return nil, fmt.Errorf("blockchain client not available: %w", err)
+	switch {
+	case client != nil && status == StatusConnected:
+		return client, nil
+	case closed:
+		return nil, ErrClosed
 	}
 
-	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
-	return cm.client, nil
+	cm.start()
+	cm.TriggerReconnect()
+	if cm.breaker.State() == BreakerOpen {
+		return nil, fmt.Errorf("blockchain client not available: %w", ErrCircuitOpen)
+	}
+	return nil, fmt.Errorf("blockchain client not available: %w", ErrNotConnected)
 }
 
 // Status returns the current connection status
@@ -160,6 +248,79 @@ func (cm *ConnectionManager) Status() (ConnectionStatus, error) {
 	return cm.status, cm.lastError
 }
 
+// BreakerState returns the state of the connection circuit breaker
+func (cm *ConnectionManager) BreakerState() BreakerState {
+	return cm.breaker.State()
+}
+
+// NewBackoff returns a backoff with the manager's reconnect settings, for
+// callers retrying their own work on top of the connection
+func (cm *ConnectionManager) NewBackoff() *Backoff {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	return NewBackoff(cm.config)
+}
+
+// URL returns the endpoint the manager connects to
+func (cm *ConnectionManager) URL() string {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	return cm.url
+}
+
+// CheckEndpoint dials rpcURL with the manager's dialer and returns the
+// network ID it serves, without touching the current connection
+func (cm *ConnectionManager) CheckEndpoint(ctx context.Context, rpcURL string) (*big.Int, error) {
+	cm.mutex.RLock()
+	dial := cm.config.Dial
+	cm.mutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, ConnectionTimeout)
+	defer cancel()
+
+	client, err := dial(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", config.RedactURL(rpcURL), err)
+	}
+	defer client.Close()
+
+	networkID, err := client.NetworkID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network ID from %s: %w", config.RedactURL(rpcURL), err)
+	}
+	return networkID, nil
+}
+
+// Reconfigure switches to new backoff and breaker settings and, when
+// rpcURL differs, drops the current client so that the background loop
+// reconnects to the new endpoint. A nil cfg.Dial keeps the current dialer.
+func (cm *ConnectionManager) Reconfigure(rpcURL string, cfg Config) {
+	cm.mutex.Lock()
+	if cfg.Dial == nil {
+		cfg.Dial = cm.config.Dial
+	}
+	cm.config = cfg.withDefaults()
+	cm.breaker.SetLimits(cm.config)
+
+	changed := rpcURL != "" && rpcURL != cm.url
+	if changed {
+		log.Printf("Switching blockchain endpoint from %s to %s", config.RedactURL(cm.url), config.RedactURL(rpcURL))
+		cm.url = rpcURL
+		if cm.client != nil {
+			cm.client.Close()
+			cm.client = nil
+		}
+		if cm.status != StatusConnecting {
+			cm.setStatus(StatusDisconnected, nil, "endpoint changed")
+		}
+	}
+	cm.mutex.Unlock()
+
+	if changed {
+		cm.TriggerReconnect()
+	}
+}
+
 // CheckHealth checks if the blockchain connection is healthy
 func (cm *ConnectionManager) CheckHealth() bool {
 	cm.mutex.RLock()
@@ -183,98 +344,226 @@ func (cm *ConnectionManager) CheckHealth() bool {
 	return true
 }
 
-// TriggerReconnect triggers a reconnection attempt if not already reconnecting
+// TriggerReconnect asks the background loop to reconnect. Requests made
+// while a backoff delay is pending are ignored.
 func (cm *ConnectionManager) TriggerReconnect() {
-	cm.mutex.Lock()
-	if !cm.isReconnecting {
-		cm.isReconnecting = true
-		cm.mutex.Unlock()
-
-		// Non-blocking send to reconnect channel
-		select {
-		case cm.reconnectChan <- struct{}{}:
-		default:
-			// Channel already has a pending reconnect
-		}
-	} else {
-		cm.mutex.Unlock()
+	// Non-blocking send to reconnect channel
+	select {
+	case cm.reconnectChan <- struct{}{}:
+	default:
+		// Channel already has a pending reconnect
 	}
 }
 
+// start runs the background loop, once
+func (cm *ConnectionManager) start() {
+	cm.loopOnce.Do(func() { go cm.startHealthCheck() })
+}
+
 // startHealthCheck starts a background routine that checks connection health
-// and triggers reconnection when necessary
+// and reconnects with exponential backoff while the connection is down
 func (cm *ConnectionManager) startHealthCheck() {
 	healthTicker := time.NewTicker(HealthCheckInterval)
-	reconnectTicker := time.NewTicker(ReconnectInterval)
 	defer healthTicker.Stop()
-	defer reconnectTicker.Stop()
+
+	backoff := cm.NewBackoff()
+	retry := time.NewTimer(0)
+	retry.Stop()
+	defer retry.Stop()
+	scheduled := false
+
+	reconnect := func() {
+		scheduled = false
+		if cm.handleReconnect() {
+			backoff.Reset()
+			return
+		}
+
+		// Wait out the backoff, or the breaker cooldown if that is longer
+		delay := max(backoff.Next(), cm.breaker.Remaining())
+		log.Printf("Next blockchain reconnect attempt in %s", delay.Round(time.Millisecond))
+		retry.Reset(delay)
+		scheduled = true
+	}
+
+	// Connect right away unless a caller has already tried, in which case
+	// its failure starts the backoff
+	cm.mutex.RLock()
+	status := cm.status
+	cm.mutex.RUnlock()
+	switch status {
+	case StatusDisconnected:
+		retry.Reset(0)
+		scheduled = true
+	case StatusError:
+		retry.Reset(backoff.Next())
+		scheduled = true
+	}
 
 	for {
 		select {
 		case <-cm.stopChan:
 			return
 		case <-healthTicker.C:
-			if !cm.CheckHealth() {
+			if !scheduled && !cm.CheckHealth() {
 				log.Println("Connection health check failed, triggering reconnect")
-				cm.TriggerReconnect()
-			}
-		case <-reconnectTicker.C:
-			// Periodically check if we need to reconnect
-			cm.mutex.RLock()
-			needsReconnect := cm.status != StatusConnected && cm.client == nil
-			cm.mutex.RUnlock()
-
-			if needsReconnect {
-				cm.TriggerReconnect()
+				reconnect()
 			}
 		case <-cm.reconnectChan:
-			cm.handleReconnect()
+			if !scheduled {
+				reconnect()
+			}
+		case <-retry.C:
+			reconnect()
 		}
 	}
 }
 
-// handleReconnect performs the actual reconnection process
-func (cm *ConnectionManager) handleReconnect() {
-	cm.mutex.Lock()
-	log.Println("Attempting to reconnect to blockchain...")
+// handleReconnect replaces an unhealthy client with a new connection and
+// reports whether the manager is connected afterwards
+func (cm *ConnectionManager) handleReconnect() bool {
+	if cm.CheckHealth() {
+		return true
+	}
 
 	// Close any existing client
+	cm.mutex.Lock()
 	if cm.client != nil {
 		cm.client.Close()
 		cm.client = nil
+		cm.setStatus(StatusDisconnected, errors.New("health check failed"), "health check failed")
 	}
-
-	cm.status = StatusConnecting
 	cm.mutex.Unlock()
 
-	// Attempt to connect
-	err := cm.Connect()
+	// Wait out an open breaker without logging an attempt
+	if cm.breaker.Remaining() > 0 {
+		return false
+	}
 
-	cm.mutex.Lock()
-	if err != nil {
-		cm.status = StatusError
-		cm.lastError = err
-		log.Printf("Reconnection failed: %v", err)
-	} else {
-		cm.status = StatusConnected
+	// The first connection is made here too, for callers of Client
+	cm.mutex.RLock()
+	reconnecting := cm.connects > 0
+	cm.mutex.RUnlock()
+
+	if reconnecting {
+		log.Println("Attempting to reconnect to blockchain...")
+	}
+	if err := cm.Connect(); err != nil {
+		if !errors.Is(err, ErrCircuitOpen) {
+			log.Printf("Connection attempt failed: %v", err)
+		}
+		return false
+	}
+
+	if reconnecting {
 		log.Println("Successfully reconnected to blockchain")
 	}
-	cm.isReconnecting = false
-	cm.mutex.Unlock()
+	return true
 }
 
-// Close stops all goroutines and closes the client connection
+// Close stops all goroutines and closes the client connection. Later calls
+// do nothing.
 func (cm *ConnectionManager) Close() {
+	cm.closeOnce.Do(cm.close)
+}
+
+func (cm *ConnectionManager) close() {
 	// Signal stop to health check goroutine
 	close(cm.stopChan)
 
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
 
+	cm.closed = true
+
 	// Close the client if it exists
 	if cm.client != nil {
 		cm.client.Close()
 		cm.client = nil
 	}
-	cm.status = StatusDisconnected
+	cm.setStatus(StatusDisconnected, nil, "closed")
+
+	// Nothing will be published after closing; subscribers still receive
+	// what was queued, ending with the closed transition
+	for sub := range cm.subscribers {
+		delete(cm.subscribers, sub)
+		sub.finish()
+	}
+}
+
+// Subscribe returns a channel receiving every status transition, in order,
+// and a function that unsubscribes and closes it. Transitions are queued
+// while the receiver is behind, never dropped; the channel is closed after
+// the manager's final transition once the manager is closed.
+func (cm *ConnectionManager) Subscribe(buffer int) (<-chan Transition, func()) {
+	sub := newSubscriber(buffer)
+
+	cm.mutex.Lock()
+	if cm.closed {
+		sub.finish()
+	} else {
+		cm.subscribers[sub] = struct{}{}
+	}
+	cm.mutex.Unlock()
+
+	return sub.ch, func() {
+		cm.mutex.Lock()
+		delete(cm.subscribers, sub)
+		cm.mutex.Unlock()
+		sub.cancel()
+	}
+}
+
+// Transitions returns the most recent status transitions, oldest first
+func (cm *ConnectionManager) Transitions() []Transition {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	return append([]Transition(nil), cm.transitions...)
+}
+
+// Stats returns uptime, the last disconnect and the reconnect count
+func (cm *ConnectionManager) Stats() ConnectionStats {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
+	stats := ConnectionStats{ConnectedSince: cm.connectedSince}
+	if cm.lastDisconnect != nil {
+		last := *cm.lastDisconnect
+		stats.LastDisconnect = &last
+	}
+	if cm.connects > 1 {
+		stats.Reconnects = cm.connects - 1
+	}
+	return stats
+}
+
+// setStatus records a status change and notifies subscribers. The caller
+// must hold the mutex.
+func (cm *ConnectionManager) setStatus(to ConnectionStatus, err error, reason string) {
+	from := cm.status
+	cm.status = to
+	cm.lastError = err
+	if from == to {
+		return
+	}
+
+	transition := Transition{From: from, To: to, Err: err, Reason: reason, At: time.Now()}
+
+	cm.transitions = append(cm.transitions, transition)
+	if len(cm.transitions) > TransitionHistorySize {
+		cm.transitions = cm.transitions[len(cm.transitions)-TransitionHistorySize:]
+	}
+
+	switch {
+	case to == StatusConnected:
+		cm.connectedSince = transition.At
+		cm.connects++
+	case from == StatusConnected:
+		cm.connectedSince = time.Time{}
+		cm.lastDisconnect = &transition
+	}
+
+	for sub := range cm.subscribers {
+		sub.publish(transition)
+	}
 }
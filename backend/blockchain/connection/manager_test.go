@@ -0,0 +1,235 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var errDialRefused = errors.New("dial refused")
+
+// refuseDial is a dialer that always fails
+func refuseDial(ctx context.Context, rpcURL string) (ChainClient, error) {
+	return nil, errDialRefused
+}
+
+// quietConfig keeps the background loop from retrying during a test
+func quietConfig(dial Dialer) Config {
+	return Config{
+		InitialBackoff:   time.Hour,
+		MaxBackoff:       time.Hour,
+		FailureThreshold: 1000,
+		Dial:             dial,
+	}
+}
+
+// receive reads n transitions from ch, failing the test on timeout
+func receive(t *testing.T, ch <-chan Transition, n int) []Transition {
+	t.Helper()
+	var got []Transition
+	for len(got) < n {
+		select {
+		case transition, ok := <-ch:
+			if !ok {
+				t.Fatalf("channel closed after %d of %d transitions", len(got), n)
+			}
+			got = append(got, transition)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d of %d transitions", len(got), n)
+		}
+	}
+	return got
+}
+
+func TestSubscribeDeliversEveryTransition(t *testing.T) {
+	cm := NewConnectionManager("http://127.0.0.1:1", quietConfig(refuseDial))
+	defer cm.Close()
+
+	transitions, unsubscribe := cm.Subscribe(0)
+	defer unsubscribe()
+
+	// Nothing reads while the attempts fail, each one publishing
+	// Disconnected/Error -> Connecting -> Error
+	const attempts = 20
+	for i := 0; i < attempts; i++ {
+		if err := cm.Connect(); !errors.Is(err, errDialRefused) {
+			t.Fatalf("Connect() = %v, want %v", err, errDialRefused)
+		}
+	}
+
+	got := receive(t, transitions, 2*attempts)
+	for i, transition := range got {
+		want := StatusConnecting
+		if i%2 == 1 {
+			want = StatusError
+		}
+		if transition.To != want {
+			t.Fatalf("transition %d went to %s, want %s", i, transition.To, want)
+		}
+	}
+}
+
+func TestSubscribeClosesAfterManagerClose(t *testing.T) {
+	cm := NewConnectionManager("http://127.0.0.1:1", quietConfig(refuseDial))
+	transitions, unsubscribe := cm.Subscribe(0)
+	defer unsubscribe()
+
+	_ = cm.Connect()
+	cm.Close()
+
+	got := receive(t, transitions, 3)
+	if last := got[len(got)-1]; last.Reason != "closed" {
+		t.Errorf("last transition reason = %q, want closed", last.Reason)
+	}
+	select {
+	case _, ok := <-transitions:
+		if ok {
+			t.Error("received a transition after closing")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("channel not closed after the manager closed")
+	}
+}
+
+// stubClient is a connected ChainClient serving only NetworkID
+type stubClient struct {
+	ChainClient
+	networkID int64
+}
+
+func (c *stubClient) NetworkID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(c.networkID), nil
+}
+
+func (c *stubClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (c *stubClient) Close() {}
+
+// gatedDialer blocks each dial until the test releases it
+type gatedDialer struct {
+	dials   chan string
+	release chan error
+}
+
+func newGatedDialer() *gatedDialer {
+	return &gatedDialer{dials: make(chan string, 16), release: make(chan error)}
+}
+
+func (d *gatedDialer) dial(ctx context.Context, rpcURL string) (ChainClient, error) {
+	d.dials <- rpcURL
+	select {
+	case err := <-d.release:
+		if err != nil {
+			return nil, err
+		}
+		return &stubClient{networkID: 1}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestBreakerReleaseFreesProbe(t *testing.T) {
+	breaker := NewBreaker(Config{FailureThreshold: 1, BreakerCooldown: time.Millisecond})
+	breaker.Failure()
+	time.Sleep(5 * time.Millisecond)
+
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("probe not allowed after cooldown: %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe allowed while the first is out: %v", err)
+	}
+	breaker.Release()
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("probe not allowed after release: %v", err)
+	}
+	if state := breaker.State(); state != BreakerHalfOpen {
+		t.Errorf("state = %s, want HalfOpen", state)
+	}
+}
+
+func TestClientDoesNotDial(t *testing.T) {
+	dialer := newGatedDialer()
+	cm := NewConnectionManager("http://a.invalid", quietConfig(dialer.dial))
+	defer cm.Close()
+
+	start := time.Now()
+	if _, err := cm.Client(); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Client() = %v, want %v", err, ErrNotConnected)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Client() took %s while disconnected", elapsed)
+	}
+
+	// The background loop dials instead
+	select {
+	case <-dialer.dials:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background loop did not dial")
+	}
+	if _, err := cm.Client(); err == nil {
+		t.Fatal("Client() returned a client while dialing")
+	}
+	dialer.release <- nil
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := cm.Client(); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("not connected after the dial succeeded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAbandonedProbeKeepsBreakerUsable(t *testing.T) {
+	dialer := newGatedDialer()
+	config := quietConfig(dialer.dial)
+	config.FailureThreshold = 1
+	config.BreakerCooldown = time.Millisecond
+	cm := NewConnectionManager("http://a.invalid", config)
+	defer cm.Close()
+
+	// Open the breaker
+	go func() { dialer.release <- errDialRefused }()
+	if err := cm.Connect(); !errors.Is(err, errDialRefused) {
+		t.Fatalf("Connect() = %v, want %v", err, errDialRefused)
+	}
+	<-dialer.dials
+	time.Sleep(5 * time.Millisecond)
+
+	// The half-open probe is abandoned because the endpoint changes
+	// while it dials
+	done := make(chan error, 1)
+	go func() { done <- cm.Connect() }()
+	<-dialer.dials
+	cm.Reconfigure("http://b.invalid", Config{})
+	dialer.release <- nil
+	if err := <-done; err == nil {
+		t.Fatal("Connect() succeeded against the old endpoint")
+	}
+
+	// The next attempt may probe again and connects
+	go func() {
+		if url := <-dialer.dials; url != "http://b.invalid" {
+			t.Errorf("dialed %s, want the new endpoint", url)
+		}
+		dialer.release <- nil
+	}()
+	if err := cm.Connect(); err != nil && !errors.Is(err, ErrConnectInProgress) {
+		t.Fatalf("Connect() after the abandoned probe = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for cm.BreakerState() != BreakerClosed {
+		if time.Now().After(deadline) {
+			t.Fatalf("breaker stuck %s", cm.BreakerState())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
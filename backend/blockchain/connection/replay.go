@@ -0,0 +1,341 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrInjected is the transport error returned by ReplayTransport when an
+// error is injected
+var ErrInjected = errors.New("injected transport error")
+
+// rpcMessage is a JSON-RPC request or response
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// rpcFixture is a recorded call and its answer
+type rpcFixture struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// RPCFixturePath returns the file the ordinal-th occurrence of a call,
+// counting from 1, is recorded under:
+// <dir>/<method>/<hash of params>-<ordinal>.json. Repeated calls such as
+// eth_blockNumber keep the order they were made in. Params are compared
+// after re-encoding, so key order and whitespace do not matter.
+func RPCFixturePath(dir, method string, params json.RawMessage, ordinal int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%d.json", fixtureKey(method, params), ordinal))
+}
+
+// fixtureKey identifies a call by method and params: <method>/<hash of params>
+func fixtureKey(method string, params json.RawMessage) string {
+	canonical := []byte("null")
+	if len(params) > 0 {
+		var decoded interface{}
+		if err := json.Unmarshal(params, &decoded); err == nil {
+			canonical, _ = json.Marshal(decoded)
+		}
+	}
+	sum := sha256.Sum256(canonical)
+	return filepath.Join(method, hex.EncodeToString(sum[:8]))
+}
+
+// DialHTTP returns a Dialer that connects over HTTP through transport,
+// for recording or replaying JSON-RPC traffic
+func DialHTTP(transport http.RoundTripper) Dialer {
+	return func(ctx context.Context, rpcURL string) (ChainClient, error) {
+		client, err := rpc.DialOptions(ctx, rpcURL, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+		if err != nil {
+			return nil, err
+		}
+		return &rpcClient{Client: ethclient.NewClient(client)}, nil
+	}
+}
+
+// NewFixtureDialer returns a Dialer whose JSON-RPC traffic is served from
+// fixtures in dir, or forwarded to the node and recorded there when record
+// is true
+func NewFixtureDialer(dir string, record bool, config ReplayConfig) Dialer {
+	if record {
+		return DialHTTP(&RecordingTransport{Dir: dir, Next: http.DefaultTransport})
+	}
+	return DialHTTP(&ReplayTransport{Dir: dir, Config: config})
+}
+
+// RecordingTransport forwards JSON-RPC requests and writes each call's
+// answer to disk in the layout ReplayTransport reads, numbering repeated
+// calls in the order they are made
+type RecordingTransport struct {
+	Dir  string
+	Next http.RoundTripper
+
+	mutex    sync.Mutex
+	recorded map[string]int
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Number the calls as they are made, before concurrent requests can
+	// complete out of order
+	calls, _, decodeErr := decodeMessages(requestBody)
+	ordinals := t.number(calls)
+
+	res, err := t.Next.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	responseBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	if decodeErr != nil {
+		return res, nil
+	}
+	answers, _, err := decodeMessages(responseBody)
+	if err != nil {
+		return res, nil
+	}
+
+	// Pair answers with their calls by ID
+	byID := make(map[string]rpcMessage, len(answers))
+	for _, answer := range answers {
+		byID[string(answer.ID)] = answer
+	}
+	for i, call := range calls {
+		answer, ok := byID[string(call.ID)]
+		if !ok || ordinals[i] == 0 {
+			continue
+		}
+
+		fixture := rpcFixture{Method: call.Method, Params: call.Params, Result: answer.Result, Error: answer.Error}
+		if err := writeFixture(RPCFixturePath(t.Dir, call.Method, call.Params, ordinals[i]), fixture); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// number assigns each call expecting an answer the next ordinal of its
+// method and params; notifications get zero
+func (t *RecordingTransport) number(calls []rpcMessage) []int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.recorded == nil {
+		t.recorded = make(map[string]int)
+	}
+	ordinals := make([]int, len(calls))
+	for i, call := range calls {
+		if len(call.ID) == 0 {
+			continue
+		}
+		key := fixtureKey(call.Method, call.Params)
+		t.recorded[key]++
+		ordinals[i] = t.recorded[key]
+	}
+	return ordinals
+}
+
+// ReplayConfig controls how recorded answers are served
+type ReplayConfig struct {
+	// Latency delays every response
+	Latency time.Duration
+
+	// ErrorRate is the fraction of requests that fail with ErrInjected
+	// instead of being answered
+	ErrorRate float64
+}
+
+// ReplayTransport answers JSON-RPC requests from recorded fixtures so the
+// connection manager and handlers can run without network access. Repeated
+// calls are answered in the order they were recorded, and with the last
+// recorded answer once those run out. Calls without a fixture receive a
+// JSON-RPC error.
+type ReplayTransport struct {
+	Dir    string
+	Config ReplayConfig
+
+	mutex  sync.Mutex
+	served map[string]int
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Config.Latency > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.Config.Latency):
+		}
+	}
+	if t.Config.ErrorRate > 0 && rand.Float64() < t.Config.ErrorRate {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, ErrInjected)
+	}
+
+	calls, batch, err := decodeMessages(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON-RPC request: %w", err)
+	}
+
+	answers := make([]rpcMessage, 0, len(calls))
+	for _, call := range calls {
+		// Notifications are not answered
+		if len(call.ID) == 0 {
+			continue
+		}
+
+		answer := rpcMessage{JSONRPC: "2.0", ID: call.ID}
+		fixture, err := t.next(call)
+		if err != nil {
+			answer.Error, _ = json.Marshal(map[string]interface{}{
+				"code":    -32000,
+				"message": fmt.Sprintf("no fixture for %s: %v", call.Method, err),
+			})
+		} else {
+			answer.Result, answer.Error = fixture.Result, fixture.Error
+			if len(answer.Result) == 0 && len(answer.Error) == 0 {
+				answer.Result = json.RawMessage("null")
+			}
+		}
+		answers = append(answers, answer)
+	}
+
+	var encoded []byte
+	if batch {
+		encoded, err = json.Marshal(answers)
+	} else if len(answers) > 0 {
+		encoded, err = json.Marshal(answers[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON-RPC response: %w", err)
+	}
+
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        "200 OK",
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(encoded)),
+		ContentLength: int64(len(encoded)),
+		Request:       req,
+	}, nil
+}
+
+// next reads the fixture answering the next occurrence of call
+func (t *ReplayTransport) next(call rpcMessage) (*rpcFixture, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.served == nil {
+		t.served = make(map[string]int)
+	}
+	key := fixtureKey(call.Method, call.Params)
+	ordinal := t.served[key] + 1
+
+	fixture, err := readFixture(RPCFixturePath(t.Dir, call.Method, call.Params, ordinal))
+	if errors.Is(err, os.ErrNotExist) && ordinal > 1 {
+		// Past the end of the recording; repeat its last answer
+		return readFixture(RPCFixturePath(t.Dir, call.Method, call.Params, ordinal-1))
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.served[key] = ordinal
+	return fixture, nil
+}
+
+// readBody reads a request body and restores it for the next transport
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON-RPC request: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// decodeMessages parses a single JSON-RPC message or a batch of them
+func decodeMessages(body []byte) (messages []rpcMessage, batch bool, err error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &messages)
+		return messages, true, err
+	}
+
+	var message rpcMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, false, err
+	}
+	return []rpcMessage{message}, false, nil
+}
+
+func readFixture(path string) (*rpcFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture rpcFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+func writeFixture(path string, fixture rpcFixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to record fixture: %w", err)
+	}
+	return nil
+}
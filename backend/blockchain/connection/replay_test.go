@@ -0,0 +1,161 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// stubNode is a JSON-RPC server whose head advances by one on every
+// eth_blockNumber call. Methods other than eth_blockNumber and eth_chainId
+// are refused.
+type stubNode struct {
+	mutex sync.Mutex
+	head  uint64
+}
+
+func (n *stubNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var call rpcMessage
+	if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	answer := rpcMessage{JSONRPC: "2.0", ID: call.ID}
+	switch call.Method {
+	case "eth_blockNumber":
+		n.mutex.Lock()
+		n.head++
+		answer.Result, _ = json.Marshal(hexutil.EncodeUint64(n.head))
+		n.mutex.Unlock()
+	case "eth_chainId":
+		answer.Result = json.RawMessage(`"0x2105"`)
+	default:
+		answer.Error = json.RawMessage(`{"code":-32601,"message":"method not available"}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(answer)
+}
+
+// record runs calls against a stub node, recording them into dir
+func record(t *testing.T, dir string, calls func(ChainClient)) {
+	t.Helper()
+	node := httptest.NewServer(&stubNode{})
+	defer node.Close()
+
+	client, err := DialHTTP(&RecordingTransport{Dir: dir, Next: http.DefaultTransport})(context.Background(), node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	calls(client)
+}
+
+// replay dials the fixtures in dir; the URL is never contacted
+func replay(t *testing.T, dir string, config ReplayConfig) ChainClient {
+	t.Helper()
+	client, err := NewFixtureDialer(dir, false, config)(context.Background(), "http://replay.invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestReplayServesRepeatedCallsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	record(t, dir, func(client ChainClient) {
+		for i := 0; i < 3; i++ {
+			if _, err := client.BlockNumber(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := client.SuggestGasPrice(ctx); err == nil {
+			t.Fatal("stub node answered eth_gasPrice")
+		}
+	})
+
+	client := replay(t, dir, ReplayConfig{})
+
+	// The recorded heads in order, then the last one repeated
+	for _, want := range []uint64{1, 2, 3, 3} {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if head != want {
+			t.Errorf("BlockNumber() = %d, want %d", head, want)
+		}
+	}
+
+	// Recorded errors are replayed as errors
+	_, err := client.SuggestGasPrice(ctx)
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32601 {
+		t.Errorf("SuggestGasPrice() = %v, want the recorded -32601 error", err)
+	}
+
+	// Calls that were never recorded fail
+	if _, err := client.ChainID(ctx); err == nil || !strings.Contains(err.Error(), "no fixture") {
+		t.Errorf("ChainID() = %v, want a missing fixture error", err)
+	}
+}
+
+func TestReplayLatency(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	record(t, dir, func(client ChainClient) {
+		if _, err := client.BlockNumber(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	const latency = 50 * time.Millisecond
+	client := replay(t, dir, ReplayConfig{Latency: latency})
+	start := time.Now()
+	if _, err := client.BlockNumber(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("answered after %s, want at least %s", elapsed, latency)
+	}
+
+	// The delay gives way to the caller's deadline
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	if _, err := client.BlockNumber(ctx); err == nil {
+		t.Error("BlockNumber() succeeded past its deadline")
+	}
+}
+
+func TestReplayErrorInjection(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	record(t, dir, func(client ChainClient) {
+		if _, err := client.BlockNumber(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	failing := replay(t, dir, ReplayConfig{ErrorRate: 1})
+	for i := 0; i < 5; i++ {
+		if _, err := failing.BlockNumber(ctx); err == nil || !strings.Contains(err.Error(), ErrInjected.Error()) {
+			t.Fatalf("BlockNumber() = %v, want %v", err, ErrInjected)
+		}
+	}
+
+	healthy := replay(t, dir, ReplayConfig{ErrorRate: 0})
+	if head, err := healthy.BlockNumber(ctx); err != nil || head != 1 {
+		t.Errorf("BlockNumber() = %d, %v, want 1", head, err)
+	}
+}
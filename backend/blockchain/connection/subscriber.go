@@ -0,0 +1,86 @@
+package connection
+
+import "sync"
+
+// subscriber delivers transitions to its channel in order. Transitions are
+// queued while the receiver is behind, so none are lost; a goroutine feeds
+// the channel from the queue.
+type subscriber struct {
+	ch   chan Transition
+	wake chan struct{}
+	stop chan struct{}
+
+	mutex    sync.Mutex
+	pending  []Transition
+	ending   bool
+	stopOnce sync.Once
+}
+
+// newSubscriber starts delivering to a channel with the given buffer
+func newSubscriber(buffer int) *subscriber {
+	s := &subscriber{
+		ch:   make(chan Transition, max(buffer, 0)),
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// publish queues a transition for delivery
+func (s *subscriber) publish(transition Transition) {
+	s.mutex.Lock()
+	s.pending = append(s.pending, transition)
+	s.mutex.Unlock()
+	s.notify()
+}
+
+// finish closes the channel once the queued transitions are delivered
+func (s *subscriber) finish() {
+	s.mutex.Lock()
+	s.ending = true
+	s.mutex.Unlock()
+	s.notify()
+}
+
+// cancel closes the channel without delivering the rest of the queue
+func (s *subscriber) cancel() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+func (s *subscriber) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) run() {
+	defer close(s.ch)
+	for {
+		s.mutex.Lock()
+		if len(s.pending) == 0 {
+			ending := s.ending
+			s.mutex.Unlock()
+			if ending {
+				return
+			}
+			select {
+			case <-s.wake:
+				continue
+			case <-s.stop:
+				return
+			}
+		}
+		next := s.pending[0]
+		s.pending[0] = Transition{}
+		s.pending = s.pending[1:]
+		s.mutex.Unlock()
+
+		select {
+		case s.ch <- next:
+		case <-s.stop:
+			return
+		}
+	}
+}
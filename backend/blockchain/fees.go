@@ -0,0 +1,169 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultGasLimit is used when gas estimation fails
+const DefaultGasLimit = uint64(300000)
+
+// gasPriceOracleABI covers the GasPriceOracle method used for L1 fee estimates
+const gasPriceOracleABI = `[
+    {
+        "inputs": [{"internalType": "bytes", "name": "_data", "type": "bytes"}],
+        "name": "getL1Fee",
+        "outputs": [{"internalType": "uint256", "name": "", "type": "uint256"}],
+        "stateMutability": "view",
+        "type": "function"
+    }
+]`
+
+// TxCost is the estimated cost of a transaction on an OP Stack chain, where
+// fees are L2 execution gas plus the L1 data fee for posting the transaction
+type TxCost struct {
+	L2GasLimit uint64
+	L2GasPrice *big.Int
+	L2Fee      *big.Int
+	L1Fee      *big.Int
+}
+
+// Total returns the combined L2 execution and L1 data fee in wei
+func (c *TxCost) Total() *big.Int {
+	return new(big.Int).Add(c.L2Fee, c.L1Fee)
+}
+
+// ProfitBreakdown is the result of an opportunity after transaction costs.
+// Token amounts are in units of QuoteToken.
+type ProfitBreakdown struct {
+	QuoteToken     string
+	GrossProfit    *big.Int
+	Fees           *big.Int
+	NetProfit      *big.Int
+	GrossProfitUSD float64
+	FeesUSD        float64
+	NetProfitUSD   float64
+	Cost           *TxCost
+}
+
+// EstimateArbitrageCost estimates the L2 gas and L1 data fee of executing a
+// route of hops swaps starting with an executeArbitrage call with the given
+// parameters. The contract takes a single swap per call and has no route
+// calldata, so every hop is costed as one more such call: the call's gas is
+// multiplied by hops and the L1 fee covers hops copies of its calldata.
+func (s *BlockchainService) EstimateArbitrageCost(ctx context.Context, fromToken, toToken common.Address, amount, minReturn *big.Int, hops int) (*TxCost, error) {
+	if hops < 1 {
+		return nil, fmt.Errorf("invalid hop count %d", hops)
+	}
+
+	// Get client with resilient connection
+	client, err := s.readyClient()
+	if err != nil {
+		return nil, err
+	}
+
+	walletAddress, err := s.GetWalletAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	call, err := s.contractABI.Pack("executeArbitrage", fromToken, toToken, amount, minReturn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack transaction data: %w", err)
+	}
+
+	// Bound the estimate by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	// Estimate L2 execution gas, falling back to the default limit
+	gasLimit, err := client.EstimateGas(ctx, ethereum.CallMsg{
+		From: walletAddress,
+		To:   &s.contractAddr,
+		Data: call,
+	})
+	if err != nil {
+		log.Printf("Gas estimation failed, using default limit: %v", err)
+		gasLimit = DefaultGasLimit
+	}
+	gasLimit *= uint64(hops)
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	nonce, err := client.PendingNonceAt(ctx, walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	// The oracle prices the unsigned transaction and accounts for the
+	// signature itself, so the signer is not asked
+	input := bytes.Repeat(call, hops)
+	tx := types.NewTransaction(nonce, s.contractAddr, big.NewInt(0), gasLimit, gasPrice, input)
+	l1Fee, err := s.GetL1Fee(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxCost{
+		L2GasLimit: gasLimit,
+		L2GasPrice: gasPrice,
+		L2Fee:      new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPrice),
+		L1Fee:      l1Fee,
+	}, nil
+}
+
+// GetL1Fee queries the chain's GasPriceOracle for the L1 data fee of an
+// unsigned transaction. It is zero on chains without one.
+func (s *BlockchainService) GetL1Fee(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	chain := s.Chain()
+	if !chain.HasL1Fee() {
+		return big.NewInt(0), nil
+	}
+
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	oracleABI, err := abi.JSON(strings.NewReader(gasPriceOracleABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gas price oracle ABI: %w", err)
+	}
+
+	serialized, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	data, err := oracleABI.Pack("getL1Fee", serialized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack getL1Fee call: %w", err)
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &chain.Contracts.GasPriceOracle,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call gas price oracle: %w", err)
+	}
+
+	out, err := oracleABI.Unpack("getL1Fee", result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack L1 fee: %w", err)
+	}
+
+	return out[0].(*big.Int), nil
+}
@@ -0,0 +1,203 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/arbie-buckets/blockchain/connection"
+)
+
+// ErrInitializing is returned by operations that need the chain ID or the
+// arbitrage contract before the service has reached them
+var ErrInitializing = errors.New("blockchain service is initializing")
+
+// ServiceState is the lifecycle state of a BlockchainService
+type ServiceState int
+
+const (
+	// StateInitializing means the chain or the contract has not been reached
+	// yet; initialization keeps retrying in the background
+	StateInitializing ServiceState = iota
+
+	// StateReady means the chain ID is known and the contract is deployed
+	StateReady
+
+	// StateFailed means the service is misconfigured and will not retry
+	StateFailed
+)
+
+// String returns a string representation of the service state
+func (s ServiceState) String() string {
+	switch s {
+	case StateInitializing:
+		return "Initializing"
+	case StateReady:
+		return "Ready"
+	case StateFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// State returns the service's lifecycle state and, while initializing or
+// failed, the error that is holding it back
+func (s *BlockchainService) State() (ServiceState, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.state, s.initErr
+}
+
+// Ready returns nil once the service is ready, ErrInitializing while it is
+// still initializing and the configuration error if it failed
+func (s *BlockchainService) Ready() error {
+	state, err := s.State()
+	switch state {
+	case StateReady:
+		return nil
+	case StateFailed:
+		return err
+	default:
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInitializing, err)
+		}
+		return ErrInitializing
+	}
+}
+
+// WaitReady blocks until the service is ready or has failed, or ctx is done
+func (s *BlockchainService) WaitReady(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.settled:
+		return s.Ready()
+	}
+}
+
+// initialize checks the chain and the arbitrage contract once the
+// connection manager has connected, retrying with backoff, and as soon as
+// the connection comes up, until both are reachable or ctx is cancelled
+func (s *BlockchainService) initialize(ctx context.Context) {
+	transitions, unsubscribe := s.connManager.Subscribe(1)
+	defer unsubscribe()
+
+	backoff := s.connManager.NewBackoff()
+	for {
+		err := s.probe(ctx)
+		if err == nil {
+			log.Printf("Blockchain service ready on %s (chain ID %s)", s.Chain().Name, s.GetChainID())
+			return
+		}
+		if state, _ := s.State(); state == StateFailed {
+			log.Printf("Warning: %s blockchain service failed: %v", s.Chain().Name, err)
+			return
+		}
+
+		s.mutex.Lock()
+		s.initErr = err
+		s.mutex.Unlock()
+
+		// The connection manager reports its own connection failures
+		delay := backoff.Next()
+		if !errors.Is(err, connection.ErrNotConnected) {
+			log.Printf("Warning: %s blockchain service not ready, retrying in %s: %v", s.Chain().Name, delay.Round(time.Millisecond), err)
+		}
+
+		if !waitRetry(ctx, delay, transitions) {
+			return
+		}
+	}
+}
+
+// waitRetry waits for delay to pass or the connection to come up. It
+// returns false when ctx is cancelled or the connection manager closed.
+func waitRetry(ctx context.Context, delay time.Duration, transitions <-chan connection.Transition) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return true
+		case transition, ok := <-transitions:
+			if !ok {
+				return false
+			}
+			if transition.To == connection.StatusConnected {
+				return true
+			}
+		}
+	}
+}
+
+// probe makes a single initialization attempt, marking the service ready
+// when it succeeds
+func (s *BlockchainService) probe(ctx context.Context) error {
+	client, err := s.connManager.Client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	chainID, err := client.NetworkID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if s.expectedChainID != 0 && chainID.Uint64() != s.expectedChainID {
+		// Retrying will not help: the endpoint is for another network
+		err := fmt.Errorf("endpoint serves chain ID %s, configured for %d", chainID, s.expectedChainID)
+		s.fail(err)
+		return err
+	}
+
+	code, err := client.CodeAt(ctx, s.contractAddr, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read arbitrage contract: %w", err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no arbitrage contract deployed at %s", s.contractAddr.Hex())
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.chainID = chainID
+	if s.chain.ChainID == 0 {
+		s.chain = ChainFor(chainID.Uint64())
+	}
+	s.state = StateReady
+	s.initErr = nil
+	close(s.settled)
+	return nil
+}
+
+// fail puts the service in the failed state
+func (s *BlockchainService) fail(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.state = StateFailed
+	s.initErr = err
+	close(s.settled)
+}
+
+// client returns the chain client unless the service has failed
+func (s *BlockchainService) client() (connection.ChainClient, error) {
+	if state, err := s.State(); state == StateFailed {
+		return nil, err
+	}
+	return s.connManager.Client()
+}
+
+// readyClient returns the chain client once the service is ready
+func (s *BlockchainService) readyClient() (connection.ChainClient, error) {
+	if err := s.Ready(); err != nil {
+		return nil, err
+	}
+	return s.connManager.Client()
+}
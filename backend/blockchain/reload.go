@@ -0,0 +1,69 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/arbie-buckets/blockchain/connection"
+	"github.com/arbie-buckets/config"
+)
+
+// ApplyConfig is a config.Applier for the connection pool: the RPC
+// endpoint, backoff, circuit breaker and timeouts. The endpoint is the one
+// configured for the service's chain. A new endpoint is dialed and must
+// serve that chain before the reload is accepted.
+func (s *BlockchainService) ApplyConfig(old, new *config.Config) (func(), error) {
+	oldChain, newChain := s.configuredChain(old), s.configuredChain(new)
+	rpcURL := newChain.RPCURL
+	if rpcURL != oldChain.RPCURL {
+		if rpcURL == config.SimulatedRPCURL || oldChain.RPCURL == config.SimulatedRPCURL {
+			return nil, errors.New("switching to or from the simulated backend requires a restart")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), connection.ConnectionTimeout)
+		defer cancel()
+
+		chainID, err := s.connManager.CheckEndpoint(ctx, rpcURL)
+		if err != nil {
+			return nil, err
+		}
+		if chainID.Uint64() != newChain.ChainID {
+			return nil, fmt.Errorf("%s serves chain ID %s, configured for %d", config.RedactURL(rpcURL), chainID, newChain.ChainID)
+		}
+	}
+
+	// Dial is left unset so the manager keeps its dialer, which may replay
+	// fixtures
+	connConfig := connectionConfig(new)
+	connConfig.Dial = nil
+	timeouts := timeoutsFromConfig(new).withDefaults()
+
+	return func() {
+		s.connManager.Reconfigure(rpcURL, connConfig)
+
+		s.mutex.Lock()
+		s.timeouts = timeouts
+		s.mutex.Unlock()
+	}, nil
+}
+
+// configuredChain returns the entry of cfg for the service's chain, which
+// is the primary chain unless one of the additional chains matches
+func (s *BlockchainService) configuredChain(cfg *config.Config) config.Chain {
+	if cfg.Chain.ChainID != s.expectedChainID {
+		for _, chain := range cfg.Chains {
+			if chain.ChainID == s.expectedChainID {
+				return chain.Chain
+			}
+		}
+	}
+	return cfg.Chain
+}
+
+// currentTimeouts returns the per-operation deadlines in effect
+func (s *BlockchainService) currentTimeouts() Timeouts {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.timeouts
+}
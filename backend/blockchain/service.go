@@ -2,31 +2,36 @@ package blockchain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"log"
 	"math/big"
-	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/joho/godotenv"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/arbie-buckets/blockchain/connection" // Ensure connection package is imported for connection management
+	"github.com/arbie-buckets/blockchain/signer"
+	"github.com/arbie-buckets/config"
 )
 
-// Global service instance for singleton pattern
+// Services by chain ID, one per configured chain, sharing one wallet
 var (
-	globalService *BlockchainService
-	serviceInit   sync.Once
-	serviceMutex  sync.RWMutex
+	services       = make(map[uint64]*BlockchainService)
+	primaryChainID uint64
+	walletSigner   signer.Signer
+	serviceInit    sync.Once
+	serviceMutex   sync.RWMutex
 )
 
 // Contract ABI definitions
@@ -68,146 +73,336 @@ const arbitrageContractABI = `[
 type TokenInfo struct {
 	Address  string
 	Symbol   string
+	Name     string
 	Decimals uint8
 }
 
+// Opportunity types reported alongside each ArbitrageOpportunity
+const (
+	OpportunityDirect   = "direct"
+	OpportunityMultiHop = "multi-hop"
+	OpportunityCEXDEX   = "cex-dex"
+)
+
 // ArbitrageOpportunity represents an arbitrage opportunity
 type ArbitrageOpportunity struct {
+	ID         string
+	Type       string
 	FromToken  string
 	ToToken    string
 	ProfitUSD  float64
 	Percentage float64
 	Timestamp  int64
+	AmountIn   *big.Int
+	AmountOut  *big.Int
+	Legs       []OpportunityLeg
+	Profit     *ProfitBreakdown
+
+	// SizedBy names the limit that determined AmountIn and PriceImpact is
+	// the expected price impact of trading it, in percent
+	SizedBy     string
+	PriceImpact float64
+
+	// SpreadBps is the spread of a cex-dex opportunity net of DEX gas and
+	// CEX taker fees, in basis points of AmountIn
+	SpreadBps float64
 }
 
-// BlockchainService provides methods to interact with blockchain
-type BlockchainService struct {
-	connManager  *connection.ConnectionManager
-	contractABI  abi.ABI
-	contractAddr common.Address
-	privateKey   *ecdsa.PrivateKey
-	chainID      *big.Int
+// OpportunityLeg is a single swap within a multi-leg opportunity
+type OpportunityLeg struct {
+	Venue     string
+	TokenIn   string
+	TokenOut  string
+	AmountIn  *big.Int
+	AmountOut *big.Int
 }
 
-// Initialize sets up the blockchain service and connection
-func Initialize() error {
-	// Load environment variables
-	err := godotenv.Load()
-	if err != nil {
-		fmt.Println("Warning: .env file not found, using system environment variables")
-	}
+// Timeouts bound each kind of RPC operation. They apply on top of the
+// caller's context, which can still cancel an operation sooner.
+type Timeouts struct {
+	// Call bounds reads: balances, contract calls, estimates and heads
+	Call time.Duration
 
-	rpcURL := os.Getenv("BASE_TESTNET_RPC_URL")
-	if rpcURL == "" {
-		println("Warning: BASE_TESTNET_RPC_URL not set, using default")
-		rpcURL = "https://sepolia.base.org" // Default to Base testnet
-	}
+	// Send bounds preparing, signing and sending a transaction
+	Send time.Duration
 
-	// Create connection manager
-	connManager := connection.NewConnectionManager(rpcURL)
+	// Wait bounds waiting for a transaction to be mined
+	Wait time.Duration
+}
 
-	// Initialize connection
-	if err := connManager.Connect(); err != nil {
-		return fmt.Errorf("failed to initialize blockchain connection: %w", err)
+// DefaultTimeouts returns the default per-operation deadlines
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Call: connection.ConnectionTimeout,
+		Send: 30 * time.Second,
+		Wait: 5 * time.Minute,
 	}
+}
 
-	// Get contract address from environment
-	contractAddress := os.Getenv("ARBITRAGE_CONTRACT_ADDRESS")
-	if contractAddress == "" {
-		return errors.New("ARBITRAGE_CONTRACT_ADDRESS environment variable not set")
+// withDefaults fills unset timeouts from DefaultTimeouts
+func (t Timeouts) withDefaults() Timeouts {
+	defaults := DefaultTimeouts()
+	if t.Call <= 0 {
+		t.Call = defaults.Call
+	}
+	if t.Send <= 0 {
+		t.Send = defaults.Send
 	}
+	if t.Wait <= 0 {
+		t.Wait = defaults.Wait
+	}
+	return t
+}
+
+// BlockchainService provides methods to interact with blockchain
+type BlockchainService struct {
+	connManager  *connection.ConnectionManager
+	contractABI  abi.ABI
+	contractAddr common.Address
+	signer       signer.Signer
+
+	// expectedChainID is the configured chain, checked once connected
+	expectedChainID uint64
+
+	// chainID is set once initialization reaches the chain; timeouts can
+	// change on reload
+	mutex    sync.RWMutex
+	chain    ChainInfo
+	timeouts Timeouts
+	chainID  *big.Int
+	state    ServiceState
+	initErr  error
+	settled  chan struct{}
+	cancel   context.CancelFunc
+
+	// stopChain shuts down the simulated chain the service runs against
+	stopChain func()
+}
 
-	// Create blockchain service
-	var initErr error
+// startSimulatedChain starts an in-memory chain for wallet with the
+// arbitrage contract at contractAddr, returning a dialer for it and a
+// function stopping it. Only builds with the simulated tag set it, keeping
+// the simulated backend out of production binaries.
+var startSimulatedChain func(wallet, contractAddr common.Address) (connection.Dialer, func(), error)
+
+// Initialize creates a blockchain service for the primary chain of cfg and
+// one for each additional chain. A service exists even when its chain is
+// unreachable: it reports StateInitializing and keeps retrying in the
+// background until the chain and the arbitrage contract respond.
+// Configuration errors are returned and leave the service in StateFailed.
+func Initialize(cfg *config.Config) error {
+	var errs []error
 	serviceInit.Do(func() {
-		service, err := NewBlockchainService(connManager, contractAddress)
-		if err != nil {
-			initErr = err
-			return
+		chains := []config.Chain{cfg.Chain}
+		for _, chain := range cfg.Chains {
+			chains = append(chains, chain.Chain)
+		}
+
+		// The wallet is opened once, which may prompt for a passphrase
+		ctx, cancel := context.WithTimeout(context.Background(), connection.ConnectionTimeout)
+		wallet, walletErr := signer.FromConfig(ctx, cfg.Wallet)
+		cancel()
+		if walletErr == nil {
+			log.Printf("Signing as %s with the %s signer", wallet.Address().Hex(), wallet.Kind())
 		}
 
-		// Set global service
 		serviceMutex.Lock()
-		globalService = service
-		serviceMutex.Unlock()
+		defer serviceMutex.Unlock()
+		primaryChainID = cfg.Chain.ChainID
+		walletSigner = wallet
+		for _, chain := range chains {
+			service, err := initializeChain(cfg, chain, wallet, walletErr)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to initialize %s blockchain service: %w", ChainFor(chain.ChainID).Name, err))
+			}
+			services[chain.ChainID] = service
+		}
 	})
+	return errors.Join(errs...)
+}
 
-	if initErr != nil {
-		return fmt.Errorf("failed to initialize blockchain service: %w", initErr)
+// initializeChain creates the service for one chain, or a failed one
+// reporting why it cannot run
+func initializeChain(cfg *config.Config, chain config.Chain, wallet signer.Signer, walletErr error) (*BlockchainService, error) {
+	// Reconnects back off exponentially and a circuit breaker fails requests
+	// fast while the node keeps refusing connections
+	connConfig := connectionConfig(cfg)
+
+	// JSON-RPC traffic is replayed from recorded fixtures when a fixtures
+	// directory is set, or recorded there. Additional chains keep theirs in
+	// a subdirectory named by chain ID.
+	if cfg.RPC.FixturesDir != "" {
+		replayConfig := connection.ReplayConfig{
+			Latency:   time.Duration(cfg.RPC.ReplayLatency),
+			ErrorRate: cfg.RPC.ReplayErrorRate,
+		}
+		dir := cfg.RPC.FixturesDir
+		if chain.ChainID != cfg.Chain.ChainID {
+			dir = filepath.Join(dir, strconv.FormatUint(chain.ChainID, 10))
+		}
+		connConfig.Dial = connection.NewFixtureDialer(dir, cfg.RPC.RecordFixtures, replayConfig)
+	}
+
+	serviceConfig := ServiceConfig{
+		ContractAddress: chain.ContractAddress,
+		ChainID:         chain.ChainID,
+		Signer:          wallet,
+		Timeouts:        timeoutsFromConfig(cfg),
+	}
+	setupErr := walletErr
+
+	// The simulated RPC URL runs offline against an in-memory chain, in
+	// builds that include it
+	var stopChain func()
+	if setupErr == nil && chain.RPCURL == config.SimulatedRPCURL {
+		if startSimulatedChain == nil {
+			setupErr = errors.New("simulated backend not included in this build; build with -tags simulated")
+		} else {
+			connConfig.Dial, stopChain, setupErr = startSimulatedChain(wallet.Address(), common.HexToAddress(chain.ContractAddress))
+			if setupErr == nil {
+				log.Println("Using simulated blockchain backend")
+			}
+		}
 	}
 
-	return nil
+	// Create connection manager
+	connManager := connection.NewConnectionManager(chain.RPCURL, connConfig)
+
+	// Create blockchain service, or a failed one reporting why it cannot run
+	var service *BlockchainService
+	if setupErr == nil {
+		service, setupErr = NewBlockchainService(connManager, serviceConfig)
+	}
+	if setupErr != nil {
+		service = newService(connManager, serviceConfig)
+		service.fail(setupErr)
+	}
+	service.stopChain = stopChain
+	return service, setupErr
+}
+
+// connectionConfig returns the backoff and breaker settings of cfg, leaving
+// Dial to the caller
+func connectionConfig(cfg *config.Config) connection.Config {
+	connConfig := connection.DefaultConfig()
+	connConfig.InitialBackoff = time.Duration(cfg.RPC.InitialBackoff)
+	connConfig.MaxBackoff = time.Duration(cfg.RPC.MaxBackoff)
+	connConfig.FailureThreshold = cfg.RPC.FailureThreshold
+	connConfig.BreakerCooldown = time.Duration(cfg.RPC.BreakerCooldown)
+	return connConfig
 }
 
-// GetService returns the global blockchain service instance
+// timeoutsFromConfig returns the per-operation deadlines of cfg
+func timeoutsFromConfig(cfg *config.Config) Timeouts {
+	return Timeouts{
+		Call: time.Duration(cfg.RPC.CallTimeout),
+		Send: time.Duration(cfg.RPC.SendTimeout),
+		Wait: time.Duration(cfg.RPC.WaitTimeout),
+	}
+}
+
+// GetService returns the blockchain service of the primary chain
 func GetService() *BlockchainService {
 	serviceMutex.RLock()
 	defer serviceMutex.RUnlock()
-	return globalService
+	return services[primaryChainID]
 }
 
-// NewBlockchainService creates a new instance of the blockchain service
-func NewBlockchainService(connManager *connection.ConnectionManager, contractAddress string) (*BlockchainService, error) {
-	// Parse contract ABI
-	parsedABI, err := abi.JSON(strings.NewReader(arbitrageContractABI))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse contract ABI: %w", err)
-	}
+// GetServiceForChain returns the blockchain service of a configured chain
+func GetServiceForChain(chainID uint64) (*BlockchainService, bool) {
+	serviceMutex.RLock()
+	defer serviceMutex.RUnlock()
+	service, ok := services[chainID]
+	return service, ok
+}
 
-	// Get private key from environment
-	privateKeyHex := os.Getenv("TEST_WALLET_PK_1")
-	if privateKeyHex == "" {
-		return nil, errors.New("TEST_WALLET_PK_1 environment variable not set")
-	}
+// GetServices returns the service of every configured chain, primary first
+// and the rest by chain ID
+func GetServices() []*BlockchainService {
+	serviceMutex.RLock()
+	defer serviceMutex.RUnlock()
 
-	// Remove "0x" prefix if present
-	if strings.HasPrefix(privateKeyHex, "0x") {
-		privateKeyHex = privateKeyHex[2:]
+	list := make([]*BlockchainService, 0, len(services))
+	for _, service := range services {
+		list = append(list, service)
 	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].Chain().ChainID, list[j].Chain().ChainID
+		if a == primaryChainID || b == primaryChainID {
+			return a == primaryChainID && b != primaryChainID
+		}
+		return a < b
+	})
+	return list
+}
 
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %w", err)
-	}
+// ServiceConfig configures a BlockchainService
+type ServiceConfig struct {
+	ContractAddress string
+	Signer          signer.Signer
 
-	// Get the blockchain client
-	client, err := connManager.Client()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get blockchain client: %w", err)
+	// ChainID is the chain the endpoint must serve; zero accepts any
+	ChainID uint64
+
+	Timeouts Timeouts
+}
+
+// NewBlockchainService creates a new instance of the blockchain service,
+// filling unset timeouts from DefaultTimeouts. The service starts in
+// StateInitializing and connects in the background until it is closed.
+func NewBlockchainService(connManager *connection.ConnectionManager, config ServiceConfig) (*BlockchainService, error) {
+	if config.ContractAddress == "" {
+		return nil, errors.New("arbitrage contract address not configured")
+	}
+	if !common.IsHexAddress(config.ContractAddress) {
+		return nil, fmt.Errorf("invalid arbitrage contract address %q", config.ContractAddress)
+	}
+	if config.Signer == nil {
+		return nil, signer.ErrNotConfigured
 	}
 
-	// Get chain ID
-	ctx, cancel := context.WithTimeout(context.Background(), connection.ConnectionTimeout)
-	defer cancel()
+	service := newService(connManager, config)
 
-	chainID, err := client.NetworkID(ctx)
+	ctx, cancel := context.WithCancel(context.Background())
+	service.cancel = cancel
+	go service.initialize(ctx)
+
+	return service, nil
+}
+
+// newService creates a service in StateInitializing without starting it
+func newService(connManager *connection.ConnectionManager, config ServiceConfig) *BlockchainService {
+	// The ABI is a constant, so parsing cannot fail at runtime
+	parsedABI, err := abi.JSON(strings.NewReader(arbitrageContractABI))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+		panic(fmt.Sprintf("failed to parse contract ABI: %v", err))
 	}
 
 	return &BlockchainService{
-		connManager:  connManager,
-		contractABI:  parsedABI,
-		contractAddr: common.HexToAddress(contractAddress),
-		privateKey:   privateKey,
-		chainID:      chainID,
-	}, nil
+		connManager:     connManager,
+		contractABI:     parsedABI,
+		contractAddr:    common.HexToAddress(config.ContractAddress),
+		signer:          config.Signer,
+		expectedChainID: config.ChainID,
+		chain:           ChainFor(config.ChainID),
+		timeouts:        config.Timeouts.withDefaults(),
+		state:           StateInitializing,
+		settled:         make(chan struct{}),
+	}
 }
 
-// GetWalletAddress returns the wallet address corresponding to the private key
+// GetWalletAddress returns the address of the wallet signer
 func (s *BlockchainService) GetWalletAddress() (common.Address, error) {
-	publicKey := s.privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return common.Address{}, errors.New("failed to get public key")
+	if s.signer == nil {
+		return common.Address{}, signer.ErrNotConfigured
 	}
-	return crypto.PubkeyToAddress(*publicKeyECDSA), nil
+	return s.signer.Address(), nil
 }
 
 // GetTokenBalance gets the balance of a specific token for the wallet
-func (s *BlockchainService) GetTokenBalance(tokenAddress common.Address) (*big.Int, error) {
+func (s *BlockchainService) GetTokenBalance(ctx context.Context, tokenAddress common.Address) (*big.Int, error) {
 	// Get client with resilient connection
-	client, err := s.connManager.Client()
+	client, err := s.client()
 	if err != nil {
 		return nil, err
 	}
@@ -224,8 +419,8 @@ func (s *BlockchainService) GetTokenBalance(tokenAddress common.Address) (*big.I
 	paddedAddress := common.LeftPadBytes(walletAddress.Bytes(), 32)
 	data = append(data, paddedAddress...)
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), connection.ConnectionTimeout)
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
 	defer cancel()
 
 	// Call the smart contract
@@ -242,10 +437,41 @@ func (s *BlockchainService) GetTokenBalance(tokenAddress common.Address) (*big.I
 	return balance, nil
 }
 
+// GetTokenDecimals reads the decimals of an ERC20 token from its contract
+func (s *BlockchainService) GetTokenDecimals(ctx context.Context, tokenAddress common.Address) (uint8, error) {
+	// Get client with resilient connection
+	client, err := s.client()
+	if err != nil {
+		return 0, err
+	}
+
+	// ERC20 decimals function signature
+	data := []byte{0x31, 0x3c, 0xe5, 0x67} // bytes4(keccak256("decimals()"))
+
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &tokenAddress,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call token contract: %w", err)
+	}
+
+	// Addresses without code return nothing, and decimals is a uint8
+	decimals := new(big.Int).SetBytes(result)
+	if len(result) != 32 || !decimals.IsUint64() || decimals.Uint64() > 255 {
+		return 0, fmt.Errorf("%s does not report ERC20 decimals", tokenAddress.Hex())
+	}
+	return uint8(decimals.Uint64()), nil
+}
+
 // GetArbitrageOpportunities fetches arbitrage opportunities from the contract
-func (s *BlockchainService) GetArbitrageOpportunities() ([]ArbitrageOpportunity, error) {
+func (s *BlockchainService) GetArbitrageOpportunities(ctx context.Context) ([]ArbitrageOpportunity, error) {
 	// Get client with resilient connection
-	client, err := s.connManager.Client()
+	client, err := s.readyClient()
 	if err != nil {
 		return nil, err
 	}
@@ -256,8 +482,8 @@ func (s *BlockchainService) GetArbitrageOpportunities() ([]ArbitrageOpportunity,
 		return nil, fmt.Errorf("failed to pack contract call: %w", err)
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), connection.ConnectionTimeout)
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
 	defer cancel()
 
 	// Call the contract
@@ -295,16 +521,22 @@ func (s *BlockchainService) GetArbitrageOpportunities() ([]ArbitrageOpportunity,
 	return opportunities, nil
 }
 
-// ExecuteArbitrage executes an arbitrage trade
-func (s *BlockchainService) ExecuteArbitrage(fromToken, toToken common.Address, amount, minReturn *big.Int) (string, error) {
+// ExecuteArbitrage executes an arbitrage trade. beforeSend, when non-nil,
+// runs once the transaction is signed, as late as possible before it is
+// sent; an error from it abandons the trade and is returned.
+func (s *BlockchainService) ExecuteArbitrage(ctx context.Context, fromToken, toToken common.Address, amount, minReturn *big.Int, beforeSend func(context.Context) error) (string, error) {
 	// Get client with resilient connection
-	client, err := s.connManager.Client()
+	client, err := s.readyClient()
 	if err != nil {
 		return "", err
 	}
 
+	// Preparing and sending share the send timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Send)
+	defer cancel()
+
 	// Create transaction auth
-	auth, err := s.createTransactionAuth()
+	auth, err := s.createTransactionAuth(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -326,14 +558,17 @@ func (s *BlockchainService) ExecuteArbitrage(fromToken, toToken common.Address,
 	)
 
 	// Sign transaction
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(s.chainID), s.privateKey)
+	signedTx, err := s.signer.SignTx(ctx, tx, s.GetChainID())
 	if err != nil {
 		return "", fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), connection.ConnectionTimeout)
-	defer cancel()
+	// Signing may have waited on a remote signer
+	if beforeSend != nil {
+		if err := beforeSend(ctx); err != nil {
+			return "", err
+		}
+	}
 
 	// Send transaction
 	err = client.SendTransaction(ctx, signedTx)
@@ -345,20 +580,24 @@ func (s *BlockchainService) ExecuteArbitrage(fromToken, toToken common.Address,
 }
 
 // WaitForTransaction waits for a transaction to be mined and returns the receipt
-func (s *BlockchainService) WaitForTransaction(txHash common.Hash) (*types.Receipt, error) {
+func (s *BlockchainService) WaitForTransaction(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
 	// Get client with resilient connection
-	client, err := s.connManager.Client()
+	client, err := s.client()
 	if err != nil {
 		return nil, err
 	}
 
+	// Bound the wait so abandoned trades do not poll forever
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Wait)
+	defer cancel()
+
 	// Get transaction
-	tx, _, err := client.TransactionByHash(context.Background(), txHash)
+	tx, _, err := client.TransactionByHash(ctx, txHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve transaction by hash: %w", err)
 	}
 
-	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	receipt, err := bind.WaitMined(ctx, client, tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
 	}
@@ -371,9 +610,9 @@ func (s *BlockchainService) WaitForTransaction(txHash common.Hash) (*types.Recei
 }
 
 // createTransactionAuth creates an authenticated transaction
-func (s *BlockchainService) createTransactionAuth() (*bind.TransactOpts, error) {
+func (s *BlockchainService) createTransactionAuth(ctx context.Context) (*bind.TransactOpts, error) {
 	// Get client with resilient connection
-	client, err := s.connManager.Client()
+	client, err := s.readyClient()
 	if err != nil {
 		return nil, err
 	}
@@ -383,10 +622,6 @@ func (s *BlockchainService) createTransactionAuth() (*bind.TransactOpts, error)
 		return nil, err
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), connection.ConnectionTimeout)
-	defer cancel()
-
 	// Get nonce
 	nonce, err := client.PendingNonceAt(ctx, walletAddress)
 	if err != nil {
@@ -399,12 +634,18 @@ func (s *BlockchainService) createTransactionAuth() (*bind.TransactOpts, error)
 		return nil, fmt.Errorf("failed to get gas price: %w", err)
 	}
 
-	// Create auth
-	auth, err := bind.NewKeyedTransactorWithChainID(s.privateKey, s.chainID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor: %w", err)
+	// Sign through the wallet signer so the key never leaves it
+	chainID := s.GetChainID()
+	auth := &bind.TransactOpts{
+		From: walletAddress,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != walletAddress {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.signer.SignTx(ctx, tx, chainID)
+		},
+		Context: ctx,
 	}
-
 	auth.Nonce = big.NewInt(int64(nonce))
 	auth.Value = big.NewInt(0)     // No ETH being sent
 	auth.GasLimit = uint64(300000) // Gas limit (can be adjusted)
@@ -413,14 +654,31 @@ func (s *BlockchainService) createTransactionAuth() (*bind.TransactOpts, error)
 	return auth, nil
 }
 
-// Close closes the blockchain connections
+// Close closes the blockchain connections of every chain, then the wallet
+// signer
 func Close() {
-	serviceMutex.RLock()
-	service := globalService
-	serviceMutex.RUnlock()
+	for _, service := range GetServices() {
+		service.Close()
+	}
+
+	serviceMutex.Lock()
+	defer serviceMutex.Unlock()
+	if walletSigner != nil {
+		walletSigner.Close()
+		walletSigner = nil
+	}
+}
 
-	if service != nil && service.connManager != nil {
-		service.connManager.Close()
+// Close stops initialization and closes the service's connection
+func (s *BlockchainService) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.connManager != nil {
+		s.connManager.Close()
+	}
+	if s.stopChain != nil {
+		s.stopChain()
 	}
 }
 
@@ -430,14 +688,45 @@ func (s *BlockchainService) GetBlockchainStatus() map[string]interface{} {
 
 	result := map[string]interface{}{
 		"connected": status == connection.StatusConnected,
-		"network":   "Base Network",
+		"network":   s.Chain().Name,
 		"status":    status.String(),
+		"chain":     s.Chain(),
+	}
+	if s.signer != nil {
+		result["signer"] = s.signer.Kind()
 	}
 
 	if err != nil {
 		result["error"] = err.Error()
 	}
 
+	// Initializing until the chain and contract have been reached
+	state, initErr := s.State()
+	result["state"] = state.String()
+	result["ready"] = state == StateReady
+	if initErr != nil {
+		result["initError"] = initErr.Error()
+	}
+
+	// Uptime, the last disconnect and the reconnect count
+	stats := s.connManager.Stats()
+	result["uptimeSeconds"] = int64(stats.Uptime().Seconds())
+	result["circuitBreaker"] = s.connManager.BreakerState().String()
+	result["reconnects"] = stats.Reconnects
+	if !stats.ConnectedSince.IsZero() {
+		result["connectedSince"] = stats.ConnectedSince.Format(time.RFC3339)
+	}
+	if stats.LastDisconnect != nil {
+		result["lastDisconnect"] = FormatTransition(*stats.LastDisconnect)
+	}
+
+	transitions := s.connManager.Transitions()
+	history := make([]map[string]interface{}, len(transitions))
+	for i, transition := range transitions {
+		history[i] = FormatTransition(transition)
+	}
+	result["transitions"] = history
+
 	if status == connection.StatusConnected {
 		// Add wallet address if available
 		walletAddress, err := s.GetWalletAddress()
@@ -446,24 +735,141 @@ func (s *BlockchainService) GetBlockchainStatus() map[string]interface{} {
 		}
 
 		// Add chain ID
-		if s.chainID != nil {
-			result["chainId"] = s.chainID.String()
+		if chainID := s.GetChainID(); chainID != nil {
+			result["chainId"] = chainID.String()
 		}
 	}
 
 	return result
 }
 
-// GetChainID returns the chain ID of the connected network
+// ConnectionStatus returns the status of the connection to the chain
+func (s *BlockchainService) ConnectionStatus() (connection.ConnectionStatus, error) {
+	return s.connManager.Status()
+}
+
+// NewBackoff returns a backoff with the connection's reconnect settings,
+// for callers retrying their own work while the node is unreachable
+func (s *BlockchainService) NewBackoff() *connection.Backoff {
+	return s.connManager.NewBackoff()
+}
+
+// SubscribeConnection returns a channel receiving every connection status
+// transition, in order and without drops, and a function that unsubscribes
+func (s *BlockchainService) SubscribeConnection(buffer int) (<-chan connection.Transition, func()) {
+	return s.connManager.Subscribe(buffer)
+}
+
+// FormatTransition converts a connection status transition into its API
+// representation
+func FormatTransition(transition connection.Transition) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"from":      transition.From.String(),
+		"to":        transition.To.String(),
+		"reason":    transition.Reason,
+		"timestamp": transition.At.Format(time.RFC3339),
+	}
+	if transition.Err != nil {
+		formatted["error"] = transition.Err.Error()
+	}
+	return formatted
+}
+
+// GetBlockNumber returns the latest block number
+func (s *BlockchainService) GetBlockNumber(ctx context.Context) (uint64, error) {
+	client, err := s.client()
+	if err != nil {
+		return 0, err
+	}
+
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	blockNumber, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block number: %w", err)
+	}
+	return blockNumber, nil
+}
+
+// GetChainID returns the chain ID of the connected network, or nil until
+// the service is ready
 func (s *BlockchainService) GetChainID() *big.Int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.chainID
 }
 
-// GetConnectionClient returns the ethclient from the connection manager
-func (s *BlockchainService) GetConnectionClient() (*ethclient.Client, error) {
-	if s.connManager == nil {
-		return nil, errors.New("connection manager not initialized")
+// Chain returns the registry entry of the service's chain. Until a service
+// configured without a chain ID is ready it is a generic entry.
+func (s *BlockchainService) Chain() ChainInfo {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.chain
+}
+
+// CallContract executes a read-only contract call through the resilient connection
+func (s *BlockchainService) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
 	}
 
-	return s.connManager.Client()
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	return client.CallContract(ctx, msg, blockNumber)
+}
+
+// GetLatestHeader returns the header of the chain head
+func (s *BlockchainService) GetLatestHeader(ctx context.Context) (*types.Header, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	return header, nil
+}
+
+// SuggestGasPrice returns the node's suggested legacy gas price
+func (s *BlockchainService) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	return gasPrice, nil
+}
+
+// BatchCall sends several JSON-RPC calls in one request. Each element's
+// Error is set when that call failed.
+func (s *BlockchainService) BatchCall(ctx context.Context, batch []rpc.BatchElem) error {
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+
+	// Bound the call by the read timeout
+	ctx, cancel := context.WithTimeout(ctx, s.currentTimeouts().Call)
+	defer cancel()
+
+	return client.BatchCallContext(ctx, batch)
 }
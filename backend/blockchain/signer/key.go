@@ -0,0 +1,50 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeySigner signs with a raw private key held in memory. It exists for
+// development against test wallets; production setups use a keystore or a
+// remote signer.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner parses a hex private key, with or without 0x prefix
+func NewKeySigner(hexKey string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		// The key is never included in errors
+		return nil, errors.New("invalid private key")
+	}
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// Address implements Signer
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx implements Signer
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// Kind implements Signer. The key may come from the environment or the
+// config file, so the backend is named after the key itself.
+func (s *KeySigner) Kind() string {
+	return "key"
+}
+
+// Close implements Signer
+func (s *KeySigner) Close() {}
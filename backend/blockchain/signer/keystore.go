@@ -0,0 +1,96 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/term"
+)
+
+// KeystoreSigner signs with an account from an encrypted go-ethereum
+// keystore file. The decrypted key stays inside the keystore and is
+// dropped on Close.
+type KeystoreSigner struct {
+	keystore *keystore.KeyStore
+	account  accounts.Account
+}
+
+// NewKeystoreSigner unlocks the keystore file at path with passphrase
+func NewKeystoreSigner(path, passphrase string) (*KeystoreSigner, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore path: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open keystore: %w", err)
+	}
+
+	// The keystore indexes the file's directory; the file itself selects
+	// the account
+	ks := keystore.NewKeyStore(filepath.Dir(path), keystore.StandardScryptN, keystore.StandardScryptP)
+	account, err := ks.Find(accounts.Account{
+		URL: accounts.URL{Scheme: keystore.KeyStoreScheme, Path: path},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no account in keystore %s: %w", path, err)
+	}
+	if err := ks.Unlock(account, passphrase); err != nil {
+		return nil, fmt.Errorf("failed to unlock keystore %s: %w", path, err)
+	}
+
+	return &KeystoreSigner{keystore: ks, account: account}, nil
+}
+
+// Address implements Signer
+func (s *KeystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+// SignTx implements Signer
+func (s *KeystoreSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.keystore.SignTx(s.account, tx, chainID)
+}
+
+// Kind implements Signer
+func (s *KeystoreSigner) Kind() string {
+	return "keystore"
+}
+
+// Close locks the account, removing the decrypted key from memory
+func (s *KeystoreSigner) Close() {
+	_ = s.keystore.Lock(s.account.Address)
+}
+
+// ReadPassphrase reads a keystore passphrase from path, or prompts for it
+// on the terminal when path is empty. A single trailing newline in the file
+// is ignored.
+func ReadPassphrase(path, keystorePath string) (string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("keystore passphrase required: set wallet.passphraseFile or run on a terminal")
+	}
+	fmt.Fprintf(os.Stderr, "Passphrase for %s: ", keystorePath)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
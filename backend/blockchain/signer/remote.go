@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ClefAPIVersion is the version of Clef's external API that RemoteSigner
+// speaks and the stand-in server reports
+const ClefAPIVersion = "6.0.0"
+
+// signTransactionResult is Clef's reply to account_signTransaction
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// RemoteSigner signs through a Clef-compatible signer over JSON-RPC. The
+// key never leaves the signer, which may ask its operator to approve each
+// transaction.
+type RemoteSigner struct {
+	client  *rpc.Client
+	url     string
+	address common.Address
+}
+
+// DialRemote connects to the signer at url, which may be HTTP, WebSocket or
+// IPC. A zero account selects the signer's first account, which requires
+// the signer to be reachable now.
+func DialRemote(ctx context.Context, url string, account common.Address) (*RemoteSigner, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to signer at %s: %w", url, err)
+	}
+	s := &RemoteSigner{client: client, url: url, address: account}
+
+	if account == (common.Address{}) {
+		var listed []common.Address
+		if err := client.CallContext(ctx, &listed, "account_list"); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to list signer accounts at %s: %w", url, err)
+		}
+		if len(listed) == 0 {
+			client.Close()
+			return nil, fmt.Errorf("signer at %s has no accounts", url)
+		}
+		s.address = listed[0]
+	}
+	return s, nil
+}
+
+// Address implements Signer
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx implements Signer. The signed transaction is checked to be the
+// one requested, from the expected account.
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args, err := sendTxArgs(s.address, tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result signTransactionResult
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("remote signer refused transaction: %w", err)
+	}
+	if result.Tx == nil {
+		return nil, errors.New("remote signer returned no transaction")
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	if signer.Hash(result.Tx) != signer.Hash(tx) {
+		return nil, errors.New("remote signer returned a different transaction")
+	}
+	sender, err := types.Sender(signer, result.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed as %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	return result.Tx, nil
+}
+
+// Kind implements Signer
+func (s *RemoteSigner) Kind() string {
+	return "remote"
+}
+
+// Close implements Signer
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// sendTxArgs converts tx into Clef's account_signTransaction arguments
+func sendTxArgs(from common.Address, tx *types.Transaction, chainID *big.Int) (*apitypes.SendTxArgs, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := &apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(from),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if to := tx.To(); to != nil {
+		address := common.NewMixedcaseAddress(*to)
+		args.To = &address
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+	return args, nil
+}
@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// NewServer returns a JSON-RPC server exposing signer through the subset of
+// Clef's external API that RemoteSigner uses. It stands in for Clef when
+// developing or testing the remote signer backend, and approves every
+// request for its account without asking.
+func NewServer(signer Signer) (*rpc.Server, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &clefAPI{signer: signer}); err != nil {
+		return nil, fmt.Errorf("failed to register signer API: %w", err)
+	}
+	return server, nil
+}
+
+// clefAPI implements the account_ namespace
+type clefAPI struct {
+	signer Signer
+}
+
+// Version returns the external API version
+func (api *clefAPI) Version() string {
+	return ClefAPIVersion
+}
+
+// List returns the signer's account
+func (api *clefAPI) List() []common.Address {
+	return []common.Address{api.signer.Address()}
+}
+
+// SignTransaction signs the transaction described by args. methodSelector
+// is accepted for compatibility and ignored.
+func (api *clefAPI) SignTransaction(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*signTransactionResult, error) {
+	if args.From.Address() != api.signer.Address() {
+		return nil, fmt.Errorf("unknown account %s", args.From.Address().Hex())
+	}
+	if args.ChainID == nil {
+		return nil, errors.New("chainId is required")
+	}
+	if args.BlobHashes != nil {
+		return nil, errors.New("blob transactions are not supported")
+	}
+
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := api.signer.SignTx(ctx, tx, (*big.Int)(args.ChainID))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Signed transaction %s (nonce %d) for %s", signed.Hash().Hex(), signed.Nonce(), api.signer.Address().Hex())
+	return &signTransactionResult{Raw: raw, Tx: signed}, nil
+}
@@ -0,0 +1,56 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/arbie-buckets/config"
+)
+
+// ErrNotConfigured is returned when no signing backend is configured
+var ErrNotConfigured = errors.New("wallet not configured: set a keystore, a remote signer or, for development, a private key")
+
+// Signer signs transactions for a single account. Backends keep the key to
+// themselves, so callers never handle key material.
+type Signer interface {
+	// Address returns the account transactions are signed for
+	Address() common.Address
+
+	// SignTx signs tx for the chain with the given ID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	// Kind names the backend: keystore, remote or key
+	Kind() string
+
+	// Close releases the key or the connection to the signer
+	Close()
+}
+
+// FromConfig creates the signer configured in wallet. A keystore without a
+// passphrase file prompts for its passphrase on the terminal.
+func FromConfig(ctx context.Context, wallet config.Wallet) (Signer, error) {
+	switch {
+	case wallet.Keystore != "":
+		passphrase, err := ReadPassphrase(wallet.PassphraseFile, wallet.Keystore)
+		if err != nil {
+			return nil, err
+		}
+		return NewKeystoreSigner(wallet.Keystore, passphrase)
+	case wallet.SignerURL != "":
+		var account common.Address
+		if wallet.Account != "" {
+			account = common.HexToAddress(wallet.Account)
+		}
+		return DialRemote(ctx, wallet.SignerURL, account)
+	case wallet.PrivateKey != "":
+		log.Println("Warning: Signing with a raw private key; use a keystore or remote signer outside development")
+		return NewKeySigner(string(wallet.PrivateKey))
+	default:
+		return nil, ErrNotConfigured
+	}
+}
@@ -0,0 +1,198 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testChainID = big.NewInt(8453)
+
+// newTestKey returns a key signer for a fresh key
+func newTestKey(t *testing.T) (*KeySigner, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewKeySigner(hexutil.Encode(crypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, key
+}
+
+// testTransactions returns an unsigned legacy and dynamic fee transaction
+func testTransactions() []*types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000A4B17")
+	return []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(1e9), Gas: 300000, To: &to, Data: []byte{0xde, 0xad}}),
+		types.NewTx(&types.DynamicFeeTx{ChainID: testChainID, Nonce: 8, GasTipCap: big.NewInt(1e6), GasFeeCap: big.NewInt(2e9), Gas: 300000, To: &to, Value: big.NewInt(1)}),
+	}
+}
+
+// serveSigner serves signer's Clef API over HTTP in process and dials it
+func serveSigner(t *testing.T, signer Signer, account common.Address) *RemoteSigner {
+	t.Helper()
+	server, err := NewServer(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	endpoint := httptest.NewServer(server)
+	t.Cleanup(endpoint.Close)
+
+	remote, err := DialRemote(context.Background(), endpoint.URL, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(remote.Close)
+	return remote
+}
+
+func TestRemoteSignerRoundTrip(t *testing.T) {
+	local, key := newTestKey(t)
+
+	// A zero account selects the signer's only account
+	remote := serveSigner(t, local, common.Address{})
+	if remote.Address() != local.Address() {
+		t.Fatalf("Address() = %s, want %s", remote.Address().Hex(), local.Address().Hex())
+	}
+	if remote.Kind() != "remote" {
+		t.Errorf("Kind() = %q, want remote", remote.Kind())
+	}
+
+	signer := types.LatestSignerForChainID(testChainID)
+	for _, tx := range testTransactions() {
+		signed, err := remote.SignTx(context.Background(), tx, testChainID)
+		if err != nil {
+			t.Fatalf("SignTx(type %d) = %v", tx.Type(), err)
+		}
+		if signer.Hash(signed) != signer.Hash(tx) {
+			t.Errorf("type %d: signed a different transaction", tx.Type())
+		}
+		if sender, err := types.Sender(signer, signed); err != nil || sender != local.Address() {
+			t.Errorf("type %d: signed by %s, %v, want %s", tx.Type(), sender.Hex(), err, local.Address().Hex())
+		}
+
+		// Signing is deterministic, so the remote result matches signing
+		// locally
+		want, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signed.Hash() != want.Hash() {
+			t.Errorf("type %d: hash %s, want %s", tx.Type(), signed.Hash().Hex(), want.Hash().Hex())
+		}
+	}
+}
+
+func TestRemoteSignerRefusesUnknownAccount(t *testing.T) {
+	local, _ := newTestKey(t)
+	other, _ := newTestKey(t)
+
+	remote := serveSigner(t, local, other.Address())
+	if _, err := remote.SignTx(context.Background(), testTransactions()[0], testChainID); err == nil || !strings.Contains(err.Error(), "unknown account") {
+		t.Errorf("SignTx() = %v, want the unknown account refused", err)
+	}
+}
+
+// impostor claims one account but signs with another key, optionally
+// altering the transaction first
+type impostor struct {
+	*KeySigner
+	claimed common.Address
+	alter   func(*types.Transaction) *types.Transaction
+}
+
+func (s *impostor) Address() common.Address { return s.claimed }
+
+func (s *impostor) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if s.alter != nil {
+		tx = s.alter(tx)
+	}
+	return s.KeySigner.SignTx(ctx, tx, chainID)
+}
+
+func TestRemoteSignerChecksSignedTransaction(t *testing.T) {
+	expected, _ := newTestKey(t)
+	other, _ := newTestKey(t)
+	tx := testTransactions()[1]
+
+	wrongKey := serveSigner(t, &impostor{KeySigner: other, claimed: expected.Address()}, expected.Address())
+	if _, err := wrongKey.SignTx(context.Background(), tx, testChainID); err == nil || !strings.Contains(err.Error(), "signed as") {
+		t.Errorf("SignTx() with another key = %v, want a sender mismatch", err)
+	}
+
+	bumpNonce := func(tx *types.Transaction) *types.Transaction {
+		inner := &types.DynamicFeeTx{
+			ChainID: tx.ChainId(), Nonce: tx.Nonce() + 1, GasTipCap: tx.GasTipCap(), GasFeeCap: tx.GasFeeCap(),
+			Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(),
+		}
+		return types.NewTx(inner)
+	}
+	altered := serveSigner(t, &impostor{KeySigner: expected, claimed: expected.Address(), alter: bumpNonce}, expected.Address())
+	if _, err := altered.SignTx(context.Background(), tx, testChainID); err == nil || !strings.Contains(err.Error(), "different transaction") {
+		t.Errorf("SignTx() of an altered transaction = %v, want a hash mismatch", err)
+	}
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.NewAccount("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := account.URL.Path
+
+	if _, err := NewKeystoreSigner(path, "wrong"); err == nil {
+		t.Error("NewKeystoreSigner() unlocked with the wrong passphrase")
+	}
+	if _, err := NewKeystoreSigner(filepath.Join(dir, "missing.json"), "correct horse"); err == nil {
+		t.Error("NewKeystoreSigner() opened a missing file")
+	}
+
+	signer, err := NewKeystoreSigner(path, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.Address() != account.Address || signer.Kind() != "keystore" {
+		t.Errorf("signer is %s (%s), want %s (keystore)", signer.Address().Hex(), signer.Kind(), account.Address.Hex())
+	}
+
+	tx := testTransactions()[1]
+	signed, err := signer.SignTx(context.Background(), tx, testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := types.Sender(types.LatestSignerForChainID(testChainID), signed); err != nil || sender != account.Address {
+		t.Errorf("signed by %s, %v, want %s", sender.Hex(), err, account.Address.Hex())
+	}
+
+	// Closing locks the account
+	signer.Close()
+	if _, err := signer.SignTx(context.Background(), tx, testChainID); err == nil {
+		t.Error("SignTx() succeeded after Close")
+	}
+}
+
+func TestKeySignerKind(t *testing.T) {
+	signer, _ := newTestKey(t)
+	if signer.Kind() != "key" {
+		t.Errorf("Kind() = %q, want key", signer.Kind())
+	}
+	if _, err := NewKeySigner("0xnot-a-key"); err == nil || strings.Contains(err.Error(), "not-a-key") {
+		t.Errorf("NewKeySigner() = %v, want an error without the key", err)
+	}
+}
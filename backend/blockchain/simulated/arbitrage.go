@@ -0,0 +1,69 @@
+package simulated
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// arbitrageCreationCode is the creation bytecode of contracts/Arbitrage.sol,
+// compiled with solc 0.8.21 for the paris EVM with the optimizer at 200
+// runs, as in the hardhat config. Recompile it when the contract changes.
+//
+//go:embed Arbitrage.bin
+var arbitrageCreationCode string
+
+// ArbitrageAccount returns a genesis account holding the Arbitrage contract
+// as its constructor leaves it when owner deploys it with routers. The
+// constructor runs for real and every storage slot it writes is copied, so
+// the contract can be placed at any configured address.
+func ArbitrageAccount(owner common.Address, routers []common.Address) (types.Account, error) {
+	addressList, err := abi.NewType("address[]", "", nil)
+	if err != nil {
+		return types.Account{}, err
+	}
+	args, err := abi.Arguments{{Type: addressList}}.Pack(append([]common.Address{}, routers...))
+	if err != nil {
+		return types.Account{}, fmt.Errorf("failed to encode constructor arguments: %w", err)
+	}
+
+	// Record the slots the constructor writes; their final values are read
+	// back once it has run
+	written := make(map[common.Hash]struct{})
+	hooks := &tracing.Hooks{
+		OnOpcode: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, returnData []byte, depth int, err error) {
+			if vm.OpCode(op) == vm.SSTORE && depth == 1 {
+				stack := scope.StackData()
+				written[common.Hash(stack[len(stack)-1].Bytes32())] = struct{}{}
+			}
+		},
+	}
+
+	cfg := &runtime.Config{
+		ChainConfig: params.MergedTestChainConfig,
+		Origin:      owner,
+		BlockNumber: new(big.Int),
+		EVMConfig:   vm.Config{Tracer: hooks},
+	}
+	input := append(common.FromHex(arbitrageCreationCode), args...)
+	code, address, _, err := runtime.Create(input, cfg)
+	if err != nil {
+		return types.Account{}, fmt.Errorf("failed to deploy arbitrage contract: %w", err)
+	}
+
+	storage := make(map[common.Hash]common.Hash, len(written))
+	for slot := range written {
+		if value := cfg.State.GetState(address, slot); value != (common.Hash{}) {
+			storage[slot] = value
+		}
+	}
+	return types.Account{Code: code, Storage: storage, Balance: new(big.Int)}, nil
+}
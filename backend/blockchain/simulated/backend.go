@@ -0,0 +1,135 @@
+package simulated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethsim "github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/arbie-buckets/blockchain/connection"
+)
+
+// RPCURL selects the simulated backend in place of a node URL
+const RPCURL = "simulated"
+
+// DefaultBlockPeriod is how often Run seals a block, matching Base
+const DefaultBlockPeriod = 2 * time.Second
+
+// ChainID is the chain ID of every simulated backend
+var ChainID = big.NewInt(1337)
+
+// Backend is an in-memory chain that runs without a node or network
+// access. Transactions are mined when Commit is called, or periodically
+// while Run is active.
+type Backend struct {
+	sim *gethsim.Backend
+}
+
+// New creates a simulated chain starting from the genesis allocation
+func New(alloc types.GenesisAlloc) *Backend {
+	return &Backend{sim: gethsim.NewBackend(alloc)}
+}
+
+// Fund returns a genesis allocation giving each address balance wei
+func Fund(balance *big.Int, addresses ...common.Address) types.GenesisAlloc {
+	alloc := make(types.GenesisAlloc, len(addresses))
+	for _, address := range addresses {
+		alloc[address] = types.Account{Balance: new(big.Int).Set(balance)}
+	}
+	return alloc
+}
+
+// Dial implements connection.Dialer. The URL is ignored and every client
+// shares the backend's chain.
+func (b *Backend) Dial(ctx context.Context, rpcURL string) (connection.ChainClient, error) {
+	return &client{Client: b.sim.Client()}, nil
+}
+
+// Commit seals pending transactions into a new block
+func (b *Backend) Commit() common.Hash {
+	return b.sim.Commit()
+}
+
+// Run commits a block every period until ctx is cancelled
+func (b *Backend) Run(ctx context.Context, period time.Duration) {
+	if period <= 0 {
+		period = DefaultBlockPeriod
+	}
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.Commit()
+		}
+	}
+}
+
+// Close shuts the chain down; it cannot be used afterwards
+func (b *Backend) Close() error {
+	return b.sim.Close()
+}
+
+// client adapts the simulated client to connection.ChainClient
+type client struct {
+	gethsim.Client
+}
+
+// NetworkID returns the chain ID, which the simulated chain also uses as
+// its network ID
+func (c *client) NetworkID(ctx context.Context) (*big.Int, error) {
+	return c.ChainID(ctx)
+}
+
+// BatchCallContext runs a batch of eth_call requests one at a time; the
+// simulated client has no raw RPC access
+func (c *client) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	for i := range batch {
+		batch[i].Error = c.call(ctx, &batch[i])
+	}
+	return nil
+}
+
+// Close is a no-op; the connection lives as long as the backend
+func (c *client) Close() {}
+
+// call executes a single eth_call batch element
+func (c *client) call(ctx context.Context, elem *rpc.BatchElem) error {
+	if elem.Method != "eth_call" || len(elem.Args) == 0 {
+		return fmt.Errorf("method %s not supported by the simulated backend", elem.Method)
+	}
+
+	args, ok := elem.Args[0].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unsupported eth_call arguments %T", elem.Args[0])
+	}
+	to, ok := args["to"].(common.Address)
+	if !ok {
+		return fmt.Errorf("unsupported eth_call target %T", args["to"])
+	}
+	data, _ := args["data"].(hexutil.Bytes)
+
+	output, err := c.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return err
+	}
+
+	// Decode the way the RPC client would
+	encoded, err := json.Marshal(hexutil.Bytes(output))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, elem.Result)
+}
@@ -0,0 +1,121 @@
+// Package simtest starts blockchain services on simulated chains for tests
+package simtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/blockchain/connection"
+	"github.com/arbie-buckets/blockchain/signer"
+	"github.com/arbie-buckets/blockchain/simulated"
+)
+
+// Contract is where the Arbitrage contract is deployed
+var Contract = common.HexToAddress("0x00000000000000000000000000000000000A4B17")
+
+// Options configures a test chain
+type Options struct {
+	// Routers are approved by the Arbitrage contract
+	Routers []common.Address
+
+	// Alloc adds genesis accounts, such as token stubs
+	Alloc types.GenesisAlloc
+
+	// Timeouts bound the service's RPC operations; unset ones default
+	Timeouts blockchain.Timeouts
+}
+
+// Chain is a ready blockchain service on a simulated chain running the real
+// Arbitrage contract, owned by a wallet funded with 100 ETH
+type Chain struct {
+	Backend *simulated.Backend
+	Service *blockchain.BlockchainService
+	Signer  *HookSigner
+	Wallet  common.Address
+
+	options Options
+}
+
+// HookSigner runs OnSign, when set, before each signature
+type HookSigner struct {
+	signer.Signer
+	OnSign func()
+}
+
+// SignTx implements signer.Signer
+func (s *HookSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if s.OnSign != nil {
+		s.OnSign()
+	}
+	return s.Signer.SignTx(ctx, tx, chainID)
+}
+
+// New starts a chain and waits for its service to be ready. Both are closed
+// when the test ends.
+func New(t *testing.T, options Options) *Chain {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySigner, err := signer.NewKeySigner(hexutil.Encode(crypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := &HookSigner{Signer: keySigner}
+
+	alloc := simulated.Fund(new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18)), wallet.Address())
+	alloc[Contract], err = simulated.ArbitrageAccount(wallet.Address(), options.Routers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for address, account := range options.Alloc {
+		alloc[address] = account
+	}
+
+	backend := simulated.New(alloc)
+	t.Cleanup(func() { backend.Close() })
+
+	chain := &Chain{Backend: backend, Signer: wallet, Wallet: wallet.Address(), options: options}
+	chain.Service = chain.NewService(t, backend.Dial)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := chain.Service.WaitReady(ctx); err != nil {
+		t.Fatalf("service not ready: %v", err)
+	}
+	return chain
+}
+
+// NewService starts another service for the chain's contract and wallet
+// that connects through dial, without waiting for it to be ready. It
+// retries failed connections quickly and is closed when the test ends.
+func (c *Chain) NewService(t *testing.T, dial connection.Dialer) *blockchain.BlockchainService {
+	t.Helper()
+	connManager := connection.NewConnectionManager(simulated.RPCURL, connection.Config{
+		Dial:            dial,
+		InitialBackoff:  10 * time.Millisecond,
+		MaxBackoff:      100 * time.Millisecond,
+		BreakerCooldown: 100 * time.Millisecond,
+	})
+	service, err := blockchain.NewBlockchainService(connManager, blockchain.ServiceConfig{
+		ContractAddress: Contract.Hex(),
+		Signer:          c.Signer,
+		ChainID:         simulated.ChainID.Uint64(),
+		Timeouts:        c.options.Timeouts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(service.Close)
+	return service
+}
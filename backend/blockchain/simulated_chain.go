@@ -0,0 +1,53 @@
+//go:build simulated
+
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/arbie-buckets/blockchain/connection"
+	"github.com/arbie-buckets/blockchain/simulated"
+)
+
+func init() {
+	startSimulatedChain = newSimulatedChain
+}
+
+// newSimulatedChain starts an in-memory chain funding the wallet with
+// 100 ETH, with the Arbitrage contract deployed at contractAddr and owned
+// by the wallet, mining a block every two seconds until stopped
+func newSimulatedChain(wallet, contractAddr common.Address) (connection.Dialer, func(), error) {
+	balance := new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+	alloc := simulated.Fund(balance, wallet)
+	contract, err := simulated.ArbitrageAccount(wallet, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to deploy simulated arbitrage contract: %w", err)
+	}
+	alloc[contractAddr] = contract
+
+	backend := simulated.New(alloc)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		backend.Run(ctx, simulated.DefaultBlockPeriod)
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			<-done
+			if err := backend.Close(); err != nil {
+				log.Printf("Warning: Failed to close simulated chain: %v", err)
+			}
+		})
+	}
+	return backend.Dial, stop, nil
+}
@@ -0,0 +1,48 @@
+package blockchain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultTokens lists the tokens the backend tracks on Base
+var DefaultTokens = []TokenInfo{
+	{Address: "0x4200000000000000000000000000000000000006", Symbol: "ETH", Name: "Ethereum", Decimals: 18},
+	{Address: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", Symbol: "USDC", Name: "USD Coin", Decimals: 6},
+	{Address: "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb", Symbol: "BASE", Name: "Base", Decimals: 18},
+}
+
+// HexAddress returns the token address as a common.Address
+func (t TokenInfo) HexAddress() common.Address {
+	return common.HexToAddress(t.Address)
+}
+
+// FindToken looks up a default token by symbol (case-insensitive) or address
+func FindToken(symbolOrAddress string) (TokenInfo, bool) {
+	for _, token := range DefaultTokens {
+		if strings.EqualFold(token.Symbol, symbolOrAddress) || strings.EqualFold(token.Address, symbolOrAddress) {
+			return token, true
+		}
+	}
+	return TokenInfo{}, false
+}
+
+// FindTokens looks up default tokens by symbol or address, returning every
+// default token when none are given
+func FindTokens(symbolsOrAddresses []string) ([]TokenInfo, error) {
+	if len(symbolsOrAddresses) == 0 {
+		return DefaultTokens, nil
+	}
+
+	tokens := make([]TokenInfo, 0, len(symbolsOrAddresses))
+	for _, symbolOrAddress := range symbolsOrAddresses {
+		token, ok := FindToken(symbolOrAddress)
+		if !ok {
+			return nil, fmt.Errorf("unknown token %q", symbolOrAddress)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,140 @@
+package cex
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/arbie-buckets/constants"
+)
+
+// BinanceBaseURL is the Binance spot REST API
+const BinanceBaseURL = "https://api.binance.com"
+
+// binanceQuoteAssets maps canonical quote assets to the ones Binance lists;
+// Binance has no USD markets, so USD is served by USDC
+var binanceQuoteAssets = map[string]string{
+	"USD": "USDC",
+}
+
+// Binance is the REST connector for Binance spot markets
+type Binance struct {
+	client  HTTPClient
+	baseURL string
+}
+
+// NewBinance creates a Binance connector
+func NewBinance(client HTTPClient) *Binance {
+	return &Binance{client: client, baseURL: BinanceBaseURL}
+}
+
+// Exchange returns constants.BINANCE
+func (b *Binance) Exchange() constants.Exchange { return constants.BINANCE }
+
+// TakerFeeBps returns the base tier taker fee
+func (b *Binance) TakerFeeBps() uint32 { return 10 }
+
+// Symbol normalizes a market to Binance's concatenated form, e.g. ETHUSDC
+func (b *Binance) Symbol(market Market) (string, error) {
+	quote := market.Quote
+	if alias, ok := binanceQuoteAssets[quote]; ok {
+		quote = alias
+	}
+	return market.Base + quote, nil
+}
+
+// binanceTicker is the response of /api/v3/ticker/24hr
+type binanceTicker struct {
+	Symbol      string `json:"symbol"`
+	BidPrice    string `json:"bidPrice"`
+	BidQty      string `json:"bidQty"`
+	AskPrice    string `json:"askPrice"`
+	AskQty      string `json:"askQty"`
+	LastPrice   string `json:"lastPrice"`
+	Volume      string `json:"volume"`
+	CloseTimeMs int64  `json:"closeTime"`
+}
+
+// binanceDepth is the response of /api/v3/depth
+type binanceDepth struct {
+	LastUpdateID uint64      `json:"lastUpdateId"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
+// Ticker returns the 24h ticker for the market
+func (b *Binance) Ticker(ctx context.Context, market Market) (*Ticker, error) {
+	symbol, err := b.Symbol(market)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw binanceTicker
+	if err := getJSON(ctx, b.client, b.baseURL+"/api/v3/ticker/24hr?symbol="+url.QueryEscape(symbol), &raw); err != nil {
+		return nil, err
+	}
+
+	ticker := &Ticker{
+		Exchange:  b.Exchange(),
+		Market:    market,
+		Symbol:    raw.Symbol,
+		Timestamp: time.UnixMilli(raw.CloseTimeMs),
+	}
+	if err := parseFloats(
+		field{raw.BidPrice, &ticker.Bid},
+		field{raw.BidQty, &ticker.BidSize},
+		field{raw.AskPrice, &ticker.Ask},
+		field{raw.AskQty, &ticker.AskSize},
+		field{raw.LastPrice, &ticker.Last},
+		field{raw.Volume, &ticker.Volume},
+	); err != nil {
+		return nil, fmt.Errorf("invalid binance ticker: %w", err)
+	}
+	return ticker, nil
+}
+
+// OrderBook returns a depth snapshot for the market
+func (b *Binance) OrderBook(ctx context.Context, market Market, depth int) (*OrderBook, error) {
+	symbol, err := b.Symbol(market)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw binanceDepth
+	endpoint := fmt.Sprintf("%s/api/v3/depth?symbol=%s&limit=%s", b.baseURL, url.QueryEscape(symbol), strconv.Itoa(binanceDepthLimit(depth)))
+	if err := getJSON(ctx, b.client, endpoint, &raw); err != nil {
+		return nil, err
+	}
+
+	bids, err := parseStringLevels(raw.Bids, depth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binance bids: %w", err)
+	}
+	asks, err := parseStringLevels(raw.Asks, depth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binance asks: %w", err)
+	}
+
+	return &OrderBook{
+		Exchange:    b.Exchange(),
+		Market:      market,
+		Symbol:      symbol,
+		Bids:        bids,
+		Asks:        asks,
+		Sequence:    raw.LastUpdateID,
+		TakerFeeBps: b.TakerFeeBps(),
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// binanceDepthLimit rounds depth up to one of the limits Binance accepts
+func binanceDepthLimit(depth int) int {
+	for _, limit := range []int{5, 10, 20, 50, 100, 500, 1000, 5000} {
+		if depth <= limit {
+			return limit
+		}
+	}
+	return 5000
+}
@@ -0,0 +1,225 @@
+package cex
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/arbie-buckets/constants"
+)
+
+// SellBase walks the bids to sell size units of the base asset and returns
+// the quote received. filled is false when the book is too shallow.
+func (b *OrderBook) SellBase(size float64) (received float64, filled bool) {
+	remaining := size
+	for _, level := range b.Bids {
+		take := min(remaining, level.Size)
+		received += take * level.Price
+		remaining -= take
+		if remaining <= 0 {
+			return received, true
+		}
+	}
+	return received, false
+}
+
+// BuyBase walks the asks spending funds units of the quote asset and returns
+// the base received. filled is false when the book is too shallow.
+func (b *OrderBook) BuyBase(funds float64) (received float64, filled bool) {
+	remaining := funds
+	for _, level := range b.Asks {
+		cost := level.Size * level.Price
+		if remaining <= cost {
+			return received + remaining/level.Price, true
+		}
+		received += level.Size
+		remaining -= cost
+	}
+	return received, false
+}
+
+// BidDepth returns the total base size and quote notional on the bid side
+func (b *OrderBook) BidDepth() (size, notional float64) {
+	for _, level := range b.Bids {
+		size += level.Size
+		notional += level.Size * level.Price
+	}
+	return size, notional
+}
+
+// AskDepth returns the total base size and quote notional on the ask side
+func (b *OrderBook) AskDepth() (size, notional float64) {
+	for _, level := range b.Asks {
+		size += level.Size
+		notional += level.Size * level.Price
+	}
+	return size, notional
+}
+
+// Mid returns the midpoint of the best bid and ask, or zero if either side
+// is empty
+func (b *OrderBook) Mid() float64 {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0
+	}
+	return (b.Bids[0].Price + b.Asks[0].Price) / 2
+}
+
+// TopOfBook is the best bid and ask of a streamed book
+type TopOfBook struct {
+	Exchange constants.Exchange
+	Market   Market
+	Bid      float64
+	BidSize  float64
+	Ask      float64
+	AskSize  float64
+	Sequence uint64
+
+	// Synced is false until a snapshot has been applied and again after the
+	// connection drops
+	Synced bool
+
+	// UpdatedAt is when the book was last confirmed current, by an update
+	// or by a heartbeat on a synced connection
+	UpdatedAt time.Time
+}
+
+// Age returns how long ago the book was last confirmed current
+func (t TopOfBook) Age() time.Duration {
+	return time.Since(t.UpdatedAt)
+}
+
+// Stale reports whether the book is out of sync or older than maxAge
+func (t TopOfBook) Stale(maxAge time.Duration) bool {
+	return !t.Synced || t.Age() > maxAge
+}
+
+// Mid returns the midpoint of the best bid and ask, or zero if either side
+// is empty
+func (t TopOfBook) Mid() float64 {
+	if t.Bid == 0 || t.Ask == 0 {
+		return 0
+	}
+	return (t.Bid + t.Ask) / 2
+}
+
+// localBook is an order book maintained from snapshots and incremental
+// updates. Levels are keyed by price; a zero size removes the level.
+type localBook struct {
+	bids      map[float64]float64
+	asks      map[float64]float64
+	depth     int
+	sequence  uint64
+	synced    bool
+	updatedAt time.Time
+}
+
+// newLocalBook creates an empty book truncated to depth levels per side,
+// or unbounded when depth is zero
+func newLocalBook(depth int) *localBook {
+	return &localBook{
+		bids:  make(map[float64]float64),
+		asks:  make(map[float64]float64),
+		depth: depth,
+	}
+}
+
+// reset replaces the book with a snapshot
+func (b *localBook) reset(bids, asks []Level, sequence uint64) {
+	b.bids = make(map[float64]float64, len(bids))
+	b.asks = make(map[float64]float64, len(asks))
+	b.sequence = sequence
+	b.synced = true
+	b.apply(bids, asks)
+}
+
+// apply applies incremental level changes
+func (b *localBook) apply(bids, asks []Level) {
+	applyLevels(b.bids, bids)
+	applyLevels(b.asks, asks)
+	if b.depth > 0 {
+		truncate(b.bids, b.depth, true)
+		truncate(b.asks, b.depth, false)
+	}
+	b.updatedAt = time.Now()
+}
+
+// levels returns up to depth levels per side in book order; depth <= 0
+// returns every level
+func (b *localBook) levels(depth int) ([]Level, []Level) {
+	return sortedLevels(b.bids, depth, true), sortedLevels(b.asks, depth, false)
+}
+
+// verify compares the book against an exchange checksum. On a mismatch
+// the book is marked out of sync, so it is not used until the stream
+// reconnects and a new snapshot resyncs it.
+func (b *localBook) verify(checksum *bookChecksum) error {
+	if checksum == nil {
+		return nil
+	}
+	bids, asks := b.levels(checksum.levels)
+	if computed := checksum.compute(bids, asks); computed != checksum.expected {
+		b.synced = false
+		return fmt.Errorf("%w: expected %d, computed %d", ErrChecksumMismatch, checksum.expected, computed)
+	}
+	return nil
+}
+
+// top returns the best bid and ask
+func (b *localBook) top(exchange constants.Exchange, market Market) TopOfBook {
+	top := TopOfBook{
+		Exchange:  exchange,
+		Market:    market,
+		Sequence:  b.sequence,
+		Synced:    b.synced,
+		UpdatedAt: b.updatedAt,
+	}
+	for price, size := range b.bids {
+		if price > top.Bid {
+			top.Bid, top.BidSize = price, size
+		}
+	}
+	for price, size := range b.asks {
+		if top.Ask == 0 || price < top.Ask {
+			top.Ask, top.AskSize = price, size
+		}
+	}
+	return top
+}
+
+func applyLevels(side map[float64]float64, levels []Level) {
+	for _, level := range levels {
+		if level.Size == 0 {
+			delete(side, level.Price)
+		} else {
+			side[level.Price] = level.Size
+		}
+	}
+}
+
+// truncate drops the levels furthest from the top beyond depth
+func truncate(side map[float64]float64, depth int, descending bool) {
+	if len(side) <= depth {
+		return
+	}
+	for _, level := range sortedLevels(side, 0, descending)[depth:] {
+		delete(side, level.Price)
+	}
+}
+
+func sortedLevels(side map[float64]float64, depth int, descending bool) []Level {
+	levels := make([]Level, 0, len(side))
+	for price, size := range side {
+		levels = append(levels, Level{Price: price, Size: size})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].Price > levels[j].Price
+		}
+		return levels[i].Price < levels[j].Price
+	})
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return levels
+}
@@ -0,0 +1,79 @@
+package cex
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/arbie-buckets/venue"
+)
+
+// BookSource provides current order books across exchanges
+type BookSource interface {
+	// OrderBooks returns up to depth levels per side of every market the
+	// source can currently vouch for
+	OrderBooks(ctx context.Context, depth int) []*OrderBook
+}
+
+// OrderBooks returns the synced, fresh books of every stream
+func (f *Feed) OrderBooks(ctx context.Context, depth int) []*OrderBook {
+	var books []*OrderBook
+	for _, stream := range f.streams {
+		for _, top := range stream.TopOfBooks() {
+			if top.Stale(stream.MaxBookAge()) {
+				continue
+			}
+			if book, ok := stream.OrderBook(top.Market, depth); ok {
+				books = append(books, book)
+			}
+		}
+	}
+	return books
+}
+
+// RESTBooks fetches order books on demand from REST connectors
+type RESTBooks struct {
+	connectors []Connector
+}
+
+// NewRESTBooks creates a book source over the connectors of every CEX venue
+func NewRESTBooks(venues []venue.Venue) *RESTBooks {
+	var connectors []Connector
+	for _, v := range venues {
+		if cexVenue, ok := v.(*Venue); ok {
+			connectors = append(connectors, cexVenue.Connector())
+		}
+	}
+	return &RESTBooks{connectors: connectors}
+}
+
+// OrderBooks fetches every listed market from every connector concurrently.
+// Failed fetches are logged and skipped.
+func (r *RESTBooks) OrderBooks(ctx context.Context, depth int) []*OrderBook {
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		books []*OrderBook
+	)
+	for _, connector := range r.connectors {
+		for _, market := range Markets {
+			wg.Add(1)
+			go func(connector Connector, market Market) {
+				defer wg.Done()
+
+				book, err := connector.OrderBook(ctx, market, depth)
+				if err != nil {
+					log.Printf("Warning: Failed to fetch %s %s order book: %v", connector.Exchange(), market, err)
+					return
+				}
+
+				mutex.Lock()
+				books = append(books, book)
+				mutex.Unlock()
+			}(connector, market)
+		}
+	}
+	wg.Wait()
+
+	return books
+}
@@ -0,0 +1,148 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/constants"
+)
+
+// ErrUnsupportedMarket is returned when an exchange does not list a market
+var ErrUnsupportedMarket = errors.New("market not supported by exchange")
+
+// Market is an exchange-independent market such as ETH-USD
+type Market struct {
+	Base  string
+	Quote string
+}
+
+// String returns the market as BASE-QUOTE
+func (m Market) String() string {
+	return m.Base + "-" + m.Quote
+}
+
+// ParseMarket parses a BASE-QUOTE market string
+func ParseMarket(value string) (Market, error) {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(value)), "-")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Market{}, fmt.Errorf("invalid market %q", value)
+	}
+	return Market{Base: parts[0], Quote: parts[1]}, nil
+}
+
+// Level is a single price level of an order book
+type Level struct {
+	Price float64
+	Size  float64
+}
+
+// Ticker is a top-of-book and last trade snapshot for a market
+type Ticker struct {
+	Exchange  constants.Exchange
+	Market    Market
+	Symbol    string
+	Bid       float64
+	BidSize   float64
+	Ask       float64
+	AskSize   float64
+	Last      float64
+	Volume    float64
+	Timestamp time.Time
+}
+
+// OrderBook is a depth snapshot for a market. Bids are sorted by descending
+// price and asks by ascending price.
+type OrderBook struct {
+	Exchange  constants.Exchange
+	Market    Market
+	Symbol    string
+	Bids      []Level
+	Asks      []Level
+	Sequence  uint64
+	Timestamp time.Time
+
+	// TakerFeeBps is the fee charged for taking liquidity from the book
+	TakerFeeBps uint32
+}
+
+// Connector fetches market data from a centralized exchange
+type Connector interface {
+	// Exchange identifies the exchange
+	Exchange() constants.Exchange
+
+	// Symbol normalizes a market to the exchange's own symbol
+	Symbol(market Market) (string, error)
+
+	// TakerFeeBps is the fee charged for crossing the spread
+	TakerFeeBps() uint32
+
+	// Ticker returns the current ticker for the market
+	Ticker(ctx context.Context, market Market) (*Ticker, error)
+
+	// OrderBook returns up to depth levels on each side of the book
+	OrderBook(ctx context.Context, market Market, depth int) (*OrderBook, error)
+}
+
+// TokenAssets maps Base token symbols to the exchange asset they track.
+// WETH trades as ETH and USDC is treated as USD.
+var TokenAssets = map[string]string{
+	"ETH":  "ETH",
+	"WETH": "ETH",
+	"USDC": "USD",
+}
+
+// Markets lists the markets the connectors are used for
+var Markets = []Market{
+	{Base: "ETH", Quote: "USD"},
+}
+
+// MarketForAssets returns the listed market trading base against quote and
+// whether it is inverted relative to the requested direction
+func MarketForAssets(base, quote string) (Market, bool, bool) {
+	for _, market := range Markets {
+		if market.Base == base && market.Quote == quote {
+			return market, false, true
+		}
+		if market.Base == quote && market.Quote == base {
+			return market, true, true
+		}
+	}
+	return Market{}, false, false
+}
+
+// NewConnector creates the REST connector for an exchange
+func NewConnector(exchange constants.Exchange, client HTTPClient) (Connector, error) {
+	switch exchange {
+	case constants.BINANCE:
+		return NewBinance(client), nil
+	case constants.KRAKEN:
+		return NewKraken(client), nil
+	case constants.GEMINI:
+		return NewGemini(client), nil
+	default:
+		return nil, fmt.Errorf("no connector for exchange %s", exchange)
+	}
+}
+
+// TokensForMarket returns the tokens among tokens that trade as the
+// market's base and quote assets
+func TokensForMarket(market Market, tokens []blockchain.TokenInfo) (base, quote blockchain.TokenInfo, ok bool) {
+	var foundBase, foundQuote bool
+	for _, token := range tokens {
+		switch TokenAssets[strings.ToUpper(token.Symbol)] {
+		case market.Base:
+			if !foundBase {
+				base, foundBase = token, true
+			}
+		case market.Quote:
+			if !foundQuote {
+				quote, foundQuote = token, true
+			}
+		}
+	}
+	return base, quote, foundBase && foundQuote
+}
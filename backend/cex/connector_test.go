@@ -0,0 +1,114 @@
+package cex
+
+import (
+	"context"
+	"testing"
+)
+
+// fixtureClient serves the recorded responses in testdata/fixtures
+func fixtureClient() HTTPClient {
+	return NewHTTPClient("testdata/fixtures", false)
+}
+
+var ethUSD = Market{Base: "ETH", Quote: "USD"}
+
+func TestConnectorSymbols(t *testing.T) {
+	client := fixtureClient()
+	tests := []struct {
+		connector Connector
+		market    Market
+		want      string
+	}{
+		// Binance has no USD markets and serves them as USDC
+		{NewBinance(client), ethUSD, "ETHUSDC"},
+		{NewBinance(client), Market{Base: "BTC", Quote: "USDT"}, "BTCUSDT"},
+		// Kraken calls BTC XBT
+		{NewKraken(client), ethUSD, "ETHUSD"},
+		{NewKraken(client), Market{Base: "BTC", Quote: "USD"}, "XBTUSD"},
+		{NewGemini(client), ethUSD, "ethusd"},
+	}
+	for _, tt := range tests {
+		got, err := tt.connector.Symbol(tt.market)
+		if err != nil {
+			t.Errorf("%s Symbol(%s) = %v", tt.connector.Exchange(), tt.market, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s Symbol(%s) = %s, want %s", tt.connector.Exchange(), tt.market, got, tt.want)
+		}
+	}
+}
+
+func TestConnectorTickers(t *testing.T) {
+	client := fixtureClient()
+	tests := []struct {
+		connector              Connector
+		bid, ask, last, volume float64
+	}{
+		{NewBinance(client), 2411.85, 2412.35, 2412.10, 48211.9084},
+		{NewKraken(client), 2411.15, 2411.65, 2411.40, 9920.4152287},
+		{NewGemini(client), 2412.40, 2412.90, 2412.65, 6120.3318},
+	}
+	for _, tt := range tests {
+		t.Run(tt.connector.Exchange().String(), func(t *testing.T) {
+			ticker, err := tt.connector.Ticker(context.Background(), ethUSD)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ticker.Bid != tt.bid || ticker.Ask != tt.ask || ticker.Last != tt.last || ticker.Volume != tt.volume {
+				t.Errorf("ticker = bid %v ask %v last %v volume %v, want %v %v %v %v",
+					ticker.Bid, ticker.Ask, ticker.Last, ticker.Volume, tt.bid, tt.ask, tt.last, tt.volume)
+			}
+			if ticker.Market != ethUSD || ticker.Exchange != tt.connector.Exchange() {
+				t.Errorf("ticker is for %s on %s", ticker.Market, ticker.Exchange)
+			}
+		})
+	}
+}
+
+func TestConnectorOrderBooks(t *testing.T) {
+	client := fixtureClient()
+	tests := []struct {
+		connector        Connector
+		bestBid, bestAsk Level
+	}{
+		{NewBinance(client), Level{Price: 2411.85, Size: 0.8}, Level{Price: 2412.35, Size: 0.8}},
+		{NewKraken(client), Level{Price: 2411.15, Size: 0.8}, Level{Price: 2411.65, Size: 0.8}},
+		{NewGemini(client), Level{Price: 2412.40, Size: 0.8}, Level{Price: 2412.90, Size: 0.8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.connector.Exchange().String(), func(t *testing.T) {
+			book, err := tt.connector.OrderBook(context.Background(), ethUSD, 50)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(book.Bids) != 10 || len(book.Asks) != 10 {
+				t.Fatalf("got %d bids and %d asks, want the 10 recorded on each side", len(book.Bids), len(book.Asks))
+			}
+			if book.Bids[0] != tt.bestBid || book.Asks[0] != tt.bestAsk {
+				t.Errorf("top of book = %v / %v, want %v / %v", book.Bids[0], book.Asks[0], tt.bestBid, tt.bestAsk)
+			}
+			if book.TakerFeeBps != tt.connector.TakerFeeBps() {
+				t.Errorf("TakerFeeBps = %d, want %d", book.TakerFeeBps, tt.connector.TakerFeeBps())
+			}
+
+			// Bids descend and asks ascend from the top of the book
+			for i := 1; i < len(book.Bids); i++ {
+				if book.Bids[i].Price >= book.Bids[i-1].Price {
+					t.Errorf("bid %d at %v is not below %v", i, book.Bids[i].Price, book.Bids[i-1].Price)
+				}
+			}
+			for i := 1; i < len(book.Asks); i++ {
+				if book.Asks[i].Price <= book.Asks[i-1].Price {
+					t.Errorf("ask %d at %v is not above %v", i, book.Asks[i].Price, book.Asks[i-1].Price)
+				}
+			}
+		})
+	}
+}
+
+func TestConnectorMissingFixture(t *testing.T) {
+	if _, err := NewGemini(fixtureClient()).Ticker(context.Background(), Market{Base: "ETH", Quote: "EUR"}); err == nil {
+		t.Error("Ticker() succeeded for a market without a fixture")
+	}
+}
@@ -0,0 +1,111 @@
+package cex
+
+import (
+	"context"
+	"sync"
+
+	"github.com/arbie-buckets/venue"
+)
+
+// Feed combines the streams of several exchanges
+type Feed struct {
+	streams []*Stream
+}
+
+// NewFeed creates a feed over streams
+func NewFeed(streams ...*Stream) *Feed {
+	return &Feed{streams: streams}
+}
+
+// NewFeedFromVenues creates a feed streaming the markets of every CEX venue
+func NewFeedFromVenues(venues []venue.Venue, config StreamConfig) (*Feed, error) {
+	var streams []*Stream
+	for _, v := range venues {
+		cexVenue, ok := v.(*Venue)
+		if !ok {
+			continue
+		}
+		stream, err := NewStream(cexVenue.Connector(), config)
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, stream)
+	}
+	return NewFeed(streams...), nil
+}
+
+// Streams returns the streams in the feed
+func (f *Feed) Streams() []*Stream {
+	return f.streams
+}
+
+// Run runs every stream until ctx is cancelled
+func (f *Feed) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, stream := range f.streams {
+		wg.Add(1)
+		go func(stream *Stream) {
+			defer wg.Done()
+			stream.Run(ctx)
+		}(stream)
+	}
+	wg.Wait()
+}
+
+// Subscribe returns a single channel receiving top-of-book changes from
+// every stream and a function that unsubscribes and closes it
+func (f *Feed) Subscribe(buffer int) (<-chan TopOfBook, func()) {
+	ch := make(chan TopOfBook, buffer)
+	for _, stream := range f.streams {
+		stream.addSubscriber(ch)
+	}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			for _, stream := range f.streams {
+				stream.removeSubscriber(ch)
+			}
+			close(ch)
+		})
+	}
+}
+
+// SubscribeBooks returns a single channel receiving book updates from
+// every stream and a function that unsubscribes and closes it. See
+// Stream.SubscribeBooks.
+func (f *Feed) SubscribeBooks(depth, buffer int) (<-chan BookUpdate, func()) {
+	sub := newBookSubscriber(depth, buffer)
+	for _, stream := range f.streams {
+		stream.addBookSubscriber(sub)
+	}
+	return sub.ch, func() {
+		for _, stream := range f.streams {
+			stream.removeBookSubscriber(sub)
+		}
+		sub.cancel()
+	}
+}
+
+// TopOfBooks returns the best bid and ask of every streamed market
+func (f *Feed) TopOfBooks() []TopOfBook {
+	var tops []TopOfBook
+	for _, stream := range f.streams {
+		tops = append(tops, stream.TopOfBooks()...)
+	}
+	return tops
+}
+
+// FreshTopOfBooks returns the books that are synced and younger than their
+// stream's maximum book age
+func (f *Feed) FreshTopOfBooks() []TopOfBook {
+	var tops []TopOfBook
+	for _, stream := range f.streams {
+		for _, top := range stream.TopOfBooks() {
+			if !top.Stale(stream.MaxBookAge()) {
+				tops = append(tops, top)
+			}
+		}
+	}
+	return tops
+}
@@ -0,0 +1,129 @@
+package cex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arbie-buckets/constants"
+)
+
+// GeminiBaseURL is the Gemini REST API
+const GeminiBaseURL = "https://api.gemini.com"
+
+// Gemini is the REST connector for Gemini spot markets
+type Gemini struct {
+	client  HTTPClient
+	baseURL string
+}
+
+// NewGemini creates a Gemini connector
+func NewGemini(client HTTPClient) *Gemini {
+	return &Gemini{client: client, baseURL: GeminiBaseURL}
+}
+
+// Exchange returns constants.GEMINI
+func (g *Gemini) Exchange() constants.Exchange { return constants.GEMINI }
+
+// TakerFeeBps returns the base tier API taker fee
+func (g *Gemini) TakerFeeBps() uint32 { return 35 }
+
+// Symbol normalizes a market to Gemini's lower-case form, e.g. ethusd
+func (g *Gemini) Symbol(market Market) (string, error) {
+	return strings.ToLower(market.Base + market.Quote), nil
+}
+
+// geminiTicker is the response of /v1/pubticker/{symbol}. The volume object
+// is keyed by asset and also carries a millisecond timestamp.
+type geminiTicker struct {
+	Bid    string                 `json:"bid"`
+	Ask    string                 `json:"ask"`
+	Last   string                 `json:"last"`
+	Volume map[string]interface{} `json:"volume"`
+}
+
+// geminiLevel is a single order book level of /v1/book/{symbol}
+type geminiLevel struct {
+	Price  string `json:"price"`
+	Amount string `json:"amount"`
+}
+
+// geminiBook is the response of /v1/book/{symbol}
+type geminiBook struct {
+	Bids []geminiLevel `json:"bids"`
+	Asks []geminiLevel `json:"asks"`
+}
+
+// Ticker returns the ticker for the market
+func (g *Gemini) Ticker(ctx context.Context, market Market) (*Ticker, error) {
+	symbol, err := g.Symbol(market)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw geminiTicker
+	if err := getJSON(ctx, g.client, g.baseURL+"/v1/pubticker/"+symbol, &raw); err != nil {
+		return nil, err
+	}
+
+	ticker := &Ticker{
+		Exchange:  g.Exchange(),
+		Market:    market,
+		Symbol:    symbol,
+		Timestamp: time.Now(),
+	}
+	if err := parseFloats(
+		field{raw.Bid, &ticker.Bid},
+		field{raw.Ask, &ticker.Ask},
+		field{raw.Last, &ticker.Last},
+	); err != nil {
+		return nil, fmt.Errorf("invalid gemini ticker: %w", err)
+	}
+
+	if volume, ok := raw.Volume[market.Base].(string); ok {
+		ticker.Volume, _ = strconv.ParseFloat(volume, 64)
+	}
+	if ms, ok := raw.Volume["timestamp"].(float64); ok {
+		ticker.Timestamp = time.UnixMilli(int64(ms))
+	}
+	return ticker, nil
+}
+
+// OrderBook returns a depth snapshot for the market
+func (g *Gemini) OrderBook(ctx context.Context, market Market, depth int) (*OrderBook, error) {
+	symbol, err := g.Symbol(market)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw geminiBook
+	endpoint := fmt.Sprintf("%s/v1/book/%s?limit_asks=%d&limit_bids=%d", g.baseURL, symbol, depth, depth)
+	if err := getJSON(ctx, g.client, endpoint, &raw); err != nil {
+		return nil, err
+	}
+
+	book := &OrderBook{
+		Exchange:    g.Exchange(),
+		Market:      market,
+		Symbol:      symbol,
+		TakerFeeBps: g.TakerFeeBps(),
+		Timestamp:   time.Now(),
+	}
+	for _, level := range raw.Bids {
+		parsed, err := parseLevel(level.Price, level.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gemini bid: %w", err)
+		}
+		book.Bids = append(book.Bids, parsed)
+	}
+	for _, level := range raw.Asks {
+		parsed, err := parseLevel(level.Price, level.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gemini ask: %w", err)
+		}
+		book.Asks = append(book.Asks, parsed)
+	}
+	return book, nil
+}
@@ -0,0 +1,135 @@
+package cex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RequestTimeout bounds a single REST request
+const RequestTimeout = 10 * time.Second
+
+// HTTPClient is the subset of *http.Client used by the connectors
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewHTTPClient returns the client used by the connectors. When fixturesDir
+// is set, responses are served from recorded fixtures instead of the
+// network, or written there when record is true.
+func NewHTTPClient(fixturesDir string, record bool) *http.Client {
+	client := &http.Client{Timeout: RequestTimeout}
+	if fixturesDir == "" {
+		return client
+	}
+
+	if record {
+		client.Transport = &RecordingTransport{Dir: fixturesDir, Next: http.DefaultTransport}
+	} else {
+		client.Transport = &FixtureTransport{Dir: fixturesDir}
+	}
+	return client
+}
+
+// getJSON fetches url and decodes the JSON body into out
+func getJSON(ctx context.Context, client HTTPClient, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", req.URL.Host, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with status %d: %s", req.URL.Host, res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
+
+// FixturePath returns the file a request's response is recorded under:
+// <dir>/<host>/<path>_<query>.json with separators flattened
+func FixturePath(dir string, req *http.Request) string {
+	name := strings.ReplaceAll(strings.Trim(req.URL.Path, "/"), "/", "_")
+	if query := req.URL.Query().Encode(); query != "" {
+		query = strings.NewReplacer("&", "_", "=", "-", "%", "").Replace(query)
+		name += "_" + query
+	}
+	return filepath.Join(dir, req.URL.Host, name+".json")
+}
+
+// FixtureTransport serves recorded responses from disk so the connectors can
+// run without network access
+type FixtureTransport struct {
+	Dir string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := FixturePath(t.Dir, req)
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture for %s: %w", req.URL, err)
+	}
+
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        "200 OK",
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// RecordingTransport forwards requests and writes successful responses to
+// disk in the layout FixtureTransport reads
+type RecordingTransport struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.Next.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	path := FixturePath(t.Dir, req)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to record fixture: %w", err)
+	}
+
+	return res, nil
+}
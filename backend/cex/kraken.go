@@ -0,0 +1,156 @@
+package cex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arbie-buckets/constants"
+)
+
+// KrakenBaseURL is the Kraken spot REST API
+const KrakenBaseURL = "https://api.kraken.com"
+
+// krakenAssets maps canonical assets to Kraken's asset codes
+var krakenAssets = map[string]string{
+	"BTC": "XBT",
+}
+
+// Kraken is the REST connector for Kraken spot markets
+type Kraken struct {
+	client  HTTPClient
+	baseURL string
+}
+
+// NewKraken creates a Kraken connector
+func NewKraken(client HTTPClient) *Kraken {
+	return &Kraken{client: client, baseURL: KrakenBaseURL}
+}
+
+// Exchange returns constants.KRAKEN
+func (k *Kraken) Exchange() constants.Exchange { return constants.KRAKEN }
+
+// TakerFeeBps returns the base tier taker fee
+func (k *Kraken) TakerFeeBps() uint32 { return 40 }
+
+// Symbol normalizes a market to Kraken's pair name, e.g. ETHUSD or XBTUSD
+func (k *Kraken) Symbol(market Market) (string, error) {
+	base, quote := market.Base, market.Quote
+	if alias, ok := krakenAssets[base]; ok {
+		base = alias
+	}
+	if alias, ok := krakenAssets[quote]; ok {
+		quote = alias
+	}
+	return base + quote, nil
+}
+
+// krakenResponse is the envelope of every Kraken public endpoint
+type krakenResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// krakenTicker is a pair entry of /0/public/Ticker; every field is an array
+// of strings such as [price, whole lot volume, lot volume]
+type krakenTicker struct {
+	Ask    []string `json:"a"`
+	Bid    []string `json:"b"`
+	Last   []string `json:"c"`
+	Volume []string `json:"v"`
+}
+
+// krakenDepth is a pair entry of /0/public/Depth; levels are
+// [price, volume, timestamp] with mixed string and number types
+type krakenDepth struct {
+	Asks [][]interface{} `json:"asks"`
+	Bids [][]interface{} `json:"bids"`
+}
+
+// Ticker returns the ticker for the market
+func (k *Kraken) Ticker(ctx context.Context, market Market) (*Ticker, error) {
+	symbol, err := k.Symbol(market)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw krakenTicker
+	if err := k.get(ctx, "/0/public/Ticker?pair="+url.QueryEscape(symbol), &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Ask) < 3 || len(raw.Bid) < 3 || len(raw.Last) < 1 || len(raw.Volume) < 2 {
+		return nil, fmt.Errorf("incomplete kraken ticker for %s", symbol)
+	}
+
+	ticker := &Ticker{
+		Exchange:  k.Exchange(),
+		Market:    market,
+		Symbol:    symbol,
+		Timestamp: time.Now(),
+	}
+	if err := parseFloats(
+		field{raw.Bid[0], &ticker.Bid},
+		field{raw.Bid[2], &ticker.BidSize},
+		field{raw.Ask[0], &ticker.Ask},
+		field{raw.Ask[2], &ticker.AskSize},
+		field{raw.Last[0], &ticker.Last},
+		field{raw.Volume[1], &ticker.Volume},
+	); err != nil {
+		return nil, fmt.Errorf("invalid kraken ticker: %w", err)
+	}
+	return ticker, nil
+}
+
+// OrderBook returns a depth snapshot for the market
+func (k *Kraken) OrderBook(ctx context.Context, market Market, depth int) (*OrderBook, error) {
+	symbol, err := k.Symbol(market)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw krakenDepth
+	if err := k.get(ctx, "/0/public/Depth?pair="+url.QueryEscape(symbol)+"&count="+strconv.Itoa(depth), &raw); err != nil {
+		return nil, err
+	}
+
+	bids, err := parseMixedLevels(raw.Bids, depth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kraken bids: %w", err)
+	}
+	asks, err := parseMixedLevels(raw.Asks, depth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kraken asks: %w", err)
+	}
+
+	return &OrderBook{
+		Exchange:    k.Exchange(),
+		Market:      market,
+		Symbol:      symbol,
+		Bids:        bids,
+		Asks:        asks,
+		TakerFeeBps: k.TakerFeeBps(),
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// get fetches a public endpoint and decodes its single pair result. Kraken
+// keys results by its internal pair name (e.g. XETHZUSD), so the only entry
+// is taken regardless of key.
+func (k *Kraken) get(ctx context.Context, path string, out interface{}) error {
+	var res krakenResponse
+	if err := getJSON(ctx, k.client, k.baseURL+path, &res); err != nil {
+		return err
+	}
+	if len(res.Error) > 0 {
+		return fmt.Errorf("kraken error: %s", strings.Join(res.Error, "; "))
+	}
+
+	for _, entry := range res.Result {
+		return json.Unmarshal(entry, out)
+	}
+	return ErrUnsupportedMarket
+}
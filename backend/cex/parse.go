@@ -0,0 +1,66 @@
+package cex
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// field pairs a raw string value with the float it should be parsed into
+type field struct {
+	raw string
+	out *float64
+}
+
+// parseFloats parses every field, stopping at the first invalid value
+func parseFloats(fields ...field) error {
+	for _, f := range fields {
+		value, err := strconv.ParseFloat(f.raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", f.raw)
+		}
+		*f.out = value
+	}
+	return nil
+}
+
+// parseLevel parses a price and size pair
+func parseLevel(price, size string) (Level, error) {
+	var level Level
+	err := parseFloats(field{price, &level.Price}, field{size, &level.Size})
+	return level, err
+}
+
+// parseStringLevels parses up to depth [price, size] string pairs
+func parseStringLevels(raw [][2]string, depth int) ([]Level, error) {
+	levels := make([]Level, 0, min(len(raw), depth))
+	for i := 0; i < len(raw) && i < depth; i++ {
+		level, err := parseLevel(raw[i][0], raw[i][1])
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// parseMixedLevels parses up to depth levels whose first two entries are
+// price and size strings, ignoring any trailing entries
+func parseMixedLevels(raw [][]interface{}, depth int) ([]Level, error) {
+	levels := make([]Level, 0, min(len(raw), depth))
+	for i := 0; i < len(raw) && i < depth; i++ {
+		if len(raw[i]) < 2 {
+			return nil, fmt.Errorf("short level %v", raw[i])
+		}
+		price, ok1 := raw[i][0].(string)
+		size, ok2 := raw[i][1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("malformed level %v", raw[i])
+		}
+		level, err := parseLevel(price, size)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
@@ -0,0 +1,57 @@
+package cex
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/price"
+)
+
+// TickerSource prices tokens from an exchange's USD ticker
+type TickerSource struct {
+	connector Connector
+}
+
+// NewTickerSource creates a price source over connector
+func NewTickerSource(connector Connector) *TickerSource {
+	return &TickerSource{connector: connector}
+}
+
+// Name returns the exchange identifier
+func (s *TickerSource) Name() string { return s.connector.Exchange().String() }
+
+// Price returns the midpoint of the token's USD market, falling back to the
+// last trade when one side of the book is empty
+func (s *TickerSource) Price(ctx context.Context, token blockchain.TokenInfo) (*price.Price, error) {
+	asset, ok := TokenAssets[strings.ToUpper(token.Symbol)]
+	if !ok || asset == "USD" {
+		return nil, fmt.Errorf("%s has no USD market for %s: %w", s.Name(), token.Symbol, price.ErrUnsupportedToken)
+	}
+
+	market, inverted, ok := MarketForAssets(asset, "USD")
+	if !ok || inverted {
+		return nil, fmt.Errorf("%s has no USD market for %s: %w", s.Name(), token.Symbol, price.ErrUnsupportedToken)
+	}
+
+	ticker, err := s.connector.Ticker(ctx, market)
+	if err != nil {
+		return nil, err
+	}
+
+	usd := ticker.Last
+	if ticker.Bid > 0 && ticker.Ask > 0 {
+		usd = (ticker.Bid + ticker.Ask) / 2
+	}
+	if usd <= 0 {
+		return nil, fmt.Errorf("%s returned no price for %s", s.Name(), market)
+	}
+
+	return &price.Price{
+		Token:     token,
+		USD:       usd,
+		Source:    s.Name(),
+		Timestamp: ticker.Timestamp,
+	}, nil
+}
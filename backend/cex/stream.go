@@ -0,0 +1,569 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/arbie-buckets/constants"
+)
+
+const (
+	// DefaultHeartbeatTimeout is how long a connection may stay silent
+	// before it is considered dead and reconnected
+	DefaultHeartbeatTimeout = 30 * time.Second
+
+	// DefaultMaxBookAge is how long a book stays usable without updates
+	DefaultMaxBookAge = 10 * time.Second
+
+	// DefaultReconnectDelay is the delay before the first reconnect; it
+	// doubles per failed attempt up to DefaultMaxReconnectDelay
+	DefaultReconnectDelay = time.Second
+
+	// DefaultMaxReconnectDelay caps the reconnect delay
+	DefaultMaxReconnectDelay = time.Minute
+
+	// writeTimeout bounds control frame and subscription writes
+	writeTimeout = 5 * time.Second
+)
+
+var (
+	// ErrSequenceGap is returned when a stream skips updates
+	ErrSequenceGap = errors.New("sequence gap in book updates")
+
+	// ErrChecksumMismatch is returned when the local book no longer matches
+	// the checksum the exchange sent with an update
+	ErrChecksumMismatch = errors.New("book checksum mismatch")
+)
+
+// eventKind identifies a decoded stream message
+type eventKind int
+
+const (
+	eventSnapshot eventKind = iota
+	eventUpdate
+	eventHeartbeat
+)
+
+// streamEvent is a decoded stream message. For updates, a zero size removes
+// the level and firstSequence..sequence is the range of exchange update IDs
+// the message covers; both are zero when the exchange does not number them.
+type streamEvent struct {
+	kind          eventKind
+	bids          []Level
+	asks          []Level
+	firstSequence uint64
+	sequence      uint64
+
+	// checksum, when set, must match the book once the event is applied
+	checksum *bookChecksum
+}
+
+// bookChecksum is the checksum an exchange sent for the top levels of a
+// book
+type bookChecksum struct {
+	expected uint32
+	levels   int
+	compute  func(bids, asks []Level) uint32
+}
+
+// Protocol speaks one exchange's WebSocket market data API. Each market is
+// streamed over its own connection.
+type Protocol interface {
+	// Symbol normalizes a market to the symbol used by the stream
+	Symbol(market Market) (string, error)
+
+	// URL returns the endpoint streaming symbol
+	URL(symbol string) string
+
+	// Subscribe returns the messages sent after connecting
+	Subscribe(symbol string) []interface{}
+
+	// Depth is the number of levels per side the book is truncated to, or
+	// zero for the full book
+	Depth() int
+
+	// RESTSnapshots reports whether the stream only carries updates, so
+	// snapshots have to be fetched over REST
+	RESTSnapshots() bool
+
+	// NewSession returns a decoder for a single connection
+	NewSession() Session
+}
+
+// Session decodes the messages of one connection
+type Session interface {
+	Decode(message []byte) ([]streamEvent, error)
+}
+
+// NewProtocol returns the stream protocol for an exchange
+func NewProtocol(exchange constants.Exchange) (Protocol, error) {
+	switch exchange {
+	case constants.BINANCE:
+		return binanceProtocol{}, nil
+	case constants.KRAKEN:
+		return krakenProtocol{}, nil
+	case constants.GEMINI:
+		return geminiProtocol{}, nil
+	default:
+		return nil, fmt.Errorf("no stream protocol for exchange %s", exchange)
+	}
+}
+
+// StreamConfig configures a market data stream
+type StreamConfig struct {
+	Markets           []Market
+	HeartbeatTimeout  time.Duration
+	MaxBookAge        time.Duration
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// DefaultStreamConfig streams every listed market
+func DefaultStreamConfig() StreamConfig {
+	return StreamConfig{
+		Markets:           Markets,
+		HeartbeatTimeout:  DefaultHeartbeatTimeout,
+		MaxBookAge:        DefaultMaxBookAge,
+		ReconnectDelay:    DefaultReconnectDelay,
+		MaxReconnectDelay: DefaultMaxReconnectDelay,
+	}
+}
+
+// Stream keeps local order books for an exchange's markets up to date over
+// WebSockets and publishes top-of-book changes to subscribers
+type Stream struct {
+	connector Connector
+	protocol  Protocol
+	config    StreamConfig
+	dialer    *websocket.Dialer
+
+	mutex           sync.RWMutex
+	books           map[Market]*localBook
+	subscribers     map[chan TopOfBook]struct{}
+	bookSubscribers map[*bookSubscriber]struct{}
+}
+
+// NewStream creates a stream for the connector's exchange, filling unset
+// config fields from DefaultStreamConfig. The connector is used for REST
+// snapshots.
+func NewStream(connector Connector, config StreamConfig) (*Stream, error) {
+	protocol, err := NewProtocol(connector.Exchange())
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := DefaultStreamConfig()
+	if len(config.Markets) == 0 {
+		config.Markets = defaults.Markets
+	}
+	if config.HeartbeatTimeout <= 0 {
+		config.HeartbeatTimeout = defaults.HeartbeatTimeout
+	}
+	if config.MaxBookAge <= 0 {
+		config.MaxBookAge = defaults.MaxBookAge
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = defaults.ReconnectDelay
+	}
+	if config.MaxReconnectDelay < config.ReconnectDelay {
+		config.MaxReconnectDelay = defaults.MaxReconnectDelay
+	}
+
+	books := make(map[Market]*localBook, len(config.Markets))
+	for _, market := range config.Markets {
+		books[market] = newLocalBook(protocol.Depth())
+	}
+
+	return &Stream{
+		connector:       connector,
+		protocol:        protocol,
+		config:          config,
+		dialer:          &websocket.Dialer{HandshakeTimeout: RequestTimeout},
+		books:           books,
+		subscribers:     make(map[chan TopOfBook]struct{}),
+		bookSubscribers: make(map[*bookSubscriber]struct{}),
+	}, nil
+}
+
+// Exchange returns the streamed exchange
+func (s *Stream) Exchange() constants.Exchange {
+	return s.connector.Exchange()
+}
+
+// MaxBookAge returns the age after which books are considered stale
+func (s *Stream) MaxBookAge() time.Duration {
+	return s.config.MaxBookAge
+}
+
+// Run streams every configured market until ctx is cancelled
+func (s *Stream) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, market := range s.config.Markets {
+		wg.Add(1)
+		go func(market Market) {
+			defer wg.Done()
+			s.runMarket(ctx, market)
+		}(market)
+	}
+	wg.Wait()
+}
+
+// Subscribe returns a channel receiving top-of-book changes and a function
+// that unsubscribes and closes it. Updates are dropped while the channel is
+// full.
+func (s *Stream) Subscribe(buffer int) (<-chan TopOfBook, func()) {
+	ch := make(chan TopOfBook, buffer)
+	s.addSubscriber(ch)
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.removeSubscriber(ch)
+			close(ch)
+		})
+	}
+}
+
+// SubscribeBooks returns a channel receiving up to depth levels per side of
+// every book after it changes, and a function that unsubscribes and closes
+// it. A depth of zero sends whole books. Updates wait for a slow receiver,
+// with only the latest kept per book.
+func (s *Stream) SubscribeBooks(depth, buffer int) (<-chan BookUpdate, func()) {
+	sub := newBookSubscriber(depth, buffer)
+	s.addBookSubscriber(sub)
+	return sub.ch, func() {
+		s.removeBookSubscriber(sub)
+		sub.cancel()
+	}
+}
+
+// TopOfBook returns the current best bid and ask of a market
+func (s *Stream) TopOfBook(market Market) (TopOfBook, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	book, ok := s.books[market]
+	if !ok {
+		return TopOfBook{}, false
+	}
+	return book.top(s.Exchange(), market), true
+}
+
+// TopOfBooks returns the best bid and ask of every streamed market
+func (s *Stream) TopOfBooks() []TopOfBook {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tops := make([]TopOfBook, 0, len(s.config.Markets))
+	for _, market := range s.config.Markets {
+		tops = append(tops, s.books[market].top(s.Exchange(), market))
+	}
+	return tops
+}
+
+// OrderBook returns a copy of up to depth levels per side of a market's
+// local book. ok is false if the market is not streamed or not synced.
+func (s *Stream) OrderBook(market Market, depth int) (*OrderBook, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	book, exists := s.books[market]
+	if !exists || !book.synced {
+		return nil, false
+	}
+	return s.orderBookLocked(market, book, depth), true
+}
+
+// orderBookLocked copies up to depth levels per side of a book. The caller
+// must hold the mutex.
+func (s *Stream) orderBookLocked(market Market, book *localBook, depth int) *OrderBook {
+	bids, asks := book.levels(depth)
+	return &OrderBook{
+		Exchange:    s.Exchange(),
+		Market:      market,
+		Bids:        bids,
+		Asks:        asks,
+		Sequence:    book.sequence,
+		TakerFeeBps: s.connector.TakerFeeBps(),
+		Timestamp:   book.updatedAt,
+	}
+}
+
+// runMarket keeps a market's connection open, reconnecting with backoff
+func (s *Stream) runMarket(ctx context.Context, market Market) {
+	delay := s.config.ReconnectDelay
+	for {
+		started := time.Now()
+		err := s.connect(ctx, market)
+		s.desync(market)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A connection that stayed up for a while resets the backoff
+		if time.Since(started) > s.config.MaxReconnectDelay {
+			delay = s.config.ReconnectDelay
+		}
+
+		log.Printf("Warning: %s %s stream disconnected: %v; reconnecting in %s", s.Exchange(), market, err, delay)
+		if err := sleep(ctx, delay); err != nil {
+			return
+		}
+		delay = min(delay*2, s.config.MaxReconnectDelay)
+	}
+}
+
+// connect streams a market over a single connection until it fails
+func (s *Stream) connect(ctx context.Context, market Market) error {
+	symbol, err := s.protocol.Symbol(market)
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := s.dialer.DialContext(ctx, s.protocol.URL(symbol), nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	// Close the connection on cancellation to unblock the pending read
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	// Server pings count as heartbeats
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(s.config.HeartbeatTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(writeTimeout))
+	})
+
+	for _, message := range s.protocol.Subscribe(symbol) {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := conn.WriteJSON(message); err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+
+	session := s.protocol.NewSession()
+	for {
+		conn.SetReadDeadline(time.Now().Add(s.config.HeartbeatTimeout))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read failed: %w", err)
+		}
+
+		events, err := session.Decode(message)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := s.handle(ctx, market, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handle applies a decoded event to the market's book
+func (s *Stream) handle(ctx context.Context, market Market, event streamEvent) error {
+	switch event.kind {
+	case eventHeartbeat:
+		s.update(market, func(book *localBook) bool {
+			if book.synced {
+				book.updatedAt = time.Now()
+			}
+			return false
+		})
+		return nil
+
+	case eventSnapshot:
+		var err error
+		s.update(market, func(book *localBook) bool {
+			book.reset(event.bids, event.asks, event.sequence)
+			err = book.verify(event.checksum)
+			return true
+		})
+		return err
+	}
+
+	synced, sequence := s.syncState(market)
+	if !synced {
+		// Update-only streams are synced from REST; others wait for their
+		// snapshot message
+		if !s.protocol.RESTSnapshots() {
+			return nil
+		}
+		var err error
+		if sequence, err = s.resync(ctx, market); err != nil {
+			return err
+		}
+	}
+
+	if event.sequence != 0 {
+		// Already contained in the snapshot
+		if event.sequence <= sequence {
+			return nil
+		}
+
+		if event.firstSequence > sequence+1 {
+			if !s.protocol.RESTSnapshots() {
+				return fmt.Errorf("%w: expected %d, got %d", ErrSequenceGap, sequence+1, event.firstSequence)
+			}
+
+			log.Printf("Warning: %s %s stream skipped from %d to %d, resyncing", s.Exchange(), market, sequence, event.firstSequence)
+			var err error
+			if sequence, err = s.resync(ctx, market); err != nil {
+				return err
+			}
+			if event.sequence <= sequence {
+				return nil
+			}
+			if event.firstSequence > sequence+1 {
+				return fmt.Errorf("%w: snapshot at %d is behind update %d", ErrSequenceGap, sequence, event.firstSequence)
+			}
+		}
+	}
+
+	var err error
+	s.update(market, func(book *localBook) bool {
+		book.apply(event.bids, event.asks)
+		if event.sequence != 0 {
+			book.sequence = event.sequence
+		}
+		err = book.verify(event.checksum)
+		return true
+	})
+	return err
+}
+
+// resync replaces the market's book with a REST snapshot and returns its
+// sequence
+func (s *Stream) resync(ctx context.Context, market Market) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	snapshot, err := s.connector.OrderBook(ctx, market, s.protocol.Depth())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch snapshot: %w", err)
+	}
+
+	s.update(market, func(book *localBook) bool {
+		book.reset(snapshot.Bids, snapshot.Asks, snapshot.Sequence)
+		return true
+	})
+	return snapshot.Sequence, nil
+}
+
+// desync marks the market's book as out of sync after a disconnect
+func (s *Stream) desync(market Market) {
+	s.update(market, func(book *localBook) bool {
+		changed := book.synced
+		book.synced = false
+		return changed
+	})
+}
+
+// syncState returns whether the market's book is synced and its sequence
+func (s *Stream) syncState(market Market) (bool, uint64) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	book := s.books[market]
+	return book.synced, book.sequence
+}
+
+// update mutates the market's book, publishes it to book subscribers and
+// publishes the top of book if it changed. mutate reports whether the book
+// may have changed.
+func (s *Stream) update(market Market, mutate func(book *localBook) bool) {
+	s.mutex.Lock()
+	book := s.books[market]
+	before := book.top(s.Exchange(), market)
+	if !mutate(book) {
+		s.mutex.Unlock()
+		return
+	}
+	after := book.top(s.Exchange(), market)
+	for sub := range s.bookSubscribers {
+		update := BookUpdate{Exchange: s.Exchange(), Market: market, MaxAge: s.config.MaxBookAge}
+		if book.synced {
+			update.Book = s.orderBookLocked(market, book, sub.depth)
+		}
+		sub.publish(update)
+	}
+	s.mutex.Unlock()
+
+	if topChanged(before, after) {
+		s.publish(after)
+	}
+}
+
+// publish sends top to every subscriber without blocking
+func (s *Stream) publish(top TopOfBook) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- top:
+		default:
+		}
+	}
+}
+
+func (s *Stream) addSubscriber(ch chan TopOfBook) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.subscribers[ch] = struct{}{}
+}
+
+func (s *Stream) removeSubscriber(ch chan TopOfBook) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.subscribers, ch)
+}
+
+func (s *Stream) addBookSubscriber(sub *bookSubscriber) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.bookSubscribers[sub] = struct{}{}
+}
+
+func (s *Stream) removeBookSubscriber(sub *bookSubscriber) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.bookSubscribers, sub)
+}
+
+// topChanged reports whether the best levels or sync state differ
+func topChanged(a, b TopOfBook) bool {
+	return a.Synced != b.Synced ||
+		a.Bid != b.Bid || a.BidSize != b.BidSize ||
+		a.Ask != b.Ask || a.AskSize != b.AskSize
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package cex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BinanceStreamURL is the Binance spot market data stream endpoint
+const BinanceStreamURL = "wss://stream.binance.com:9443/ws/"
+
+// binanceStreamDepth is the size of the REST snapshot the diff stream is
+// synced from
+const binanceStreamDepth = 100
+
+// binanceProtocol streams Binance's diff depth stream. Updates are numbered
+// by U (first) and u (last) update ID and must be applied on top of a REST
+// snapshot.
+type binanceProtocol struct{}
+
+func (binanceProtocol) Symbol(market Market) (string, error) {
+	return (&Binance{}).Symbol(market)
+}
+
+func (binanceProtocol) URL(symbol string) string {
+	return BinanceStreamURL + strings.ToLower(symbol) + "@depth@100ms"
+}
+
+func (binanceProtocol) Subscribe(symbol string) []interface{} { return nil }
+
+func (binanceProtocol) Depth() int { return binanceStreamDepth }
+
+func (binanceProtocol) RESTSnapshots() bool { return true }
+
+func (binanceProtocol) NewSession() Session { return binanceSession{} }
+
+// binanceDepthUpdate is a depthUpdate event
+type binanceDepthUpdate struct {
+	Event         string      `json:"e"`
+	Symbol        string      `json:"s"`
+	FirstUpdateID uint64      `json:"U"`
+	FinalUpdateID uint64      `json:"u"`
+	Bids          [][2]string `json:"b"`
+	Asks          [][2]string `json:"a"`
+}
+
+type binanceSession struct{}
+
+func (binanceSession) Decode(message []byte) ([]streamEvent, error) {
+	var update binanceDepthUpdate
+	if err := json.Unmarshal(message, &update); err != nil {
+		return nil, fmt.Errorf("invalid binance message: %w", err)
+	}
+	if update.Event != "depthUpdate" {
+		return nil, nil
+	}
+
+	bids, err := parseStringLevels(update.Bids, len(update.Bids))
+	if err != nil {
+		return nil, fmt.Errorf("invalid binance bids: %w", err)
+	}
+	asks, err := parseStringLevels(update.Asks, len(update.Asks))
+	if err != nil {
+		return nil, fmt.Errorf("invalid binance asks: %w", err)
+	}
+
+	return []streamEvent{{
+		kind:          eventUpdate,
+		bids:          bids,
+		asks:          asks,
+		firstSequence: update.FirstUpdateID,
+		sequence:      update.FinalUpdateID,
+	}}, nil
+}
@@ -0,0 +1,98 @@
+package cex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GeminiStreamURL is the Gemini v1 market data endpoint
+const GeminiStreamURL = "wss://api.gemini.com/v1/marketdata/"
+
+// geminiProtocol streams Gemini's v1 market data, which sends the full book
+// as "initial" changes and numbers every message, heartbeats included, with
+// socket_sequence
+type geminiProtocol struct{}
+
+func (geminiProtocol) Symbol(market Market) (string, error) {
+	return strings.ToLower(market.Base + market.Quote), nil
+}
+
+func (geminiProtocol) URL(symbol string) string {
+	return GeminiStreamURL + symbol + "?heartbeat=true&trades=false&auctions=false"
+}
+
+func (geminiProtocol) Subscribe(symbol string) []interface{} { return nil }
+
+func (geminiProtocol) Depth() int { return 0 }
+
+func (geminiProtocol) RESTSnapshots() bool { return false }
+
+func (geminiProtocol) NewSession() Session { return &geminiSession{} }
+
+// geminiMessage is an update or heartbeat message
+type geminiMessage struct {
+	Type           string        `json:"type"`
+	SocketSequence uint64        `json:"socket_sequence"`
+	Events         []geminiEvent `json:"events"`
+}
+
+type geminiEvent struct {
+	Type      string `json:"type"`
+	Side      string `json:"side"`
+	Price     string `json:"price"`
+	Remaining string `json:"remaining"`
+	Reason    string `json:"reason"`
+}
+
+// geminiSession tracks socket_sequence, which starts at zero on every
+// connection
+type geminiSession struct {
+	started  bool
+	sequence uint64
+}
+
+func (s *geminiSession) Decode(message []byte) ([]streamEvent, error) {
+	var msg geminiMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return nil, fmt.Errorf("invalid gemini message: %w", err)
+	}
+
+	expected := uint64(0)
+	if s.started {
+		expected = s.sequence + 1
+	}
+	if msg.SocketSequence != expected {
+		return nil, fmt.Errorf("%w: expected %d, got %d", ErrSequenceGap, expected, msg.SocketSequence)
+	}
+	s.started = true
+	s.sequence = msg.SocketSequence
+
+	if msg.Type == "heartbeat" {
+		return []streamEvent{{kind: eventHeartbeat}}, nil
+	}
+	if msg.Type != "update" {
+		return nil, nil
+	}
+
+	event := streamEvent{kind: eventUpdate}
+	for _, change := range msg.Events {
+		if change.Type != "change" {
+			continue
+		}
+		if change.Reason == "initial" {
+			event.kind = eventSnapshot
+		}
+
+		level, err := parseLevel(change.Price, change.Remaining)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gemini level: %w", err)
+		}
+		if change.Side == "bid" {
+			event.bids = append(event.bids, level)
+		} else {
+			event.asks = append(event.asks, level)
+		}
+	}
+	return []streamEvent{event}, nil
+}
@@ -0,0 +1,197 @@
+package cex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// KrakenStreamURL is the Kraken v2 public WebSocket endpoint
+const KrakenStreamURL = "wss://ws.kraken.com/v2"
+
+// krakenStreamDepth is the subscribed book depth. Kraken does not send
+// deletions for levels pushed out of range, so the local book is truncated
+// to the same depth.
+const krakenStreamDepth = 25
+
+// krakenChecksumDepth is the number of levels per side covered by book
+// checksums
+const krakenChecksumDepth = 10
+
+// krakenProtocol streams Kraken's v2 book channel, which sends a snapshot
+// on subscribe followed by unnumbered updates. Every book message carries a
+// CRC32 checksum of the top of the book after it is applied, formatted with
+// the pair's precisions from the instrument channel.
+type krakenProtocol struct{}
+
+// Symbol returns the v2 symbol, e.g. ETH/USD. Unlike the REST API, v2 uses
+// BTC rather than XBT.
+func (krakenProtocol) Symbol(market Market) (string, error) {
+	return market.Base + "/" + market.Quote, nil
+}
+
+func (krakenProtocol) URL(symbol string) string { return KrakenStreamURL }
+
+// Subscribe requests the instrument snapshot, for the precisions book
+// checksums are formatted with, before the book itself
+func (krakenProtocol) Subscribe(symbol string) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"method": "subscribe",
+			"params": map[string]interface{}{
+				"channel":  "instrument",
+				"snapshot": true,
+			},
+		},
+		map[string]interface{}{
+			"method": "subscribe",
+			"params": map[string]interface{}{
+				"channel":  "book",
+				"symbol":   []string{symbol},
+				"depth":    krakenStreamDepth,
+				"snapshot": true,
+			},
+		},
+	}
+}
+
+func (krakenProtocol) Depth() int { return krakenStreamDepth }
+
+func (krakenProtocol) RESTSnapshots() bool { return false }
+
+func (krakenProtocol) NewSession() Session {
+	return &krakenSession{precisions: make(map[string]krakenPrecision)}
+}
+
+// krakenMessage is a v2 channel message or method response
+type krakenMessage struct {
+	Channel string          `json:"channel"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+	Method  string          `json:"method"`
+	Success *bool           `json:"success"`
+	Error   string          `json:"error"`
+}
+
+type krakenBookEntry struct {
+	Symbol   string        `json:"symbol"`
+	Bids     []krakenLevel `json:"bids"`
+	Asks     []krakenLevel `json:"asks"`
+	Checksum uint32        `json:"checksum"`
+}
+
+type krakenLevel struct {
+	Price float64 `json:"price"`
+	Qty   float64 `json:"qty"`
+}
+
+// krakenInstruments is the data of an instrument channel message
+type krakenInstruments struct {
+	Pairs []struct {
+		Symbol         string `json:"symbol"`
+		PricePrecision int    `json:"price_precision"`
+		QtyPrecision   int    `json:"qty_precision"`
+	} `json:"pairs"`
+}
+
+// krakenPrecision is the number of decimals a pair's prices and
+// quantities are quoted with
+type krakenPrecision struct {
+	price int
+	qty   int
+}
+
+// krakenSession remembers the pair precisions seen on the connection so
+// book checksums can be verified
+type krakenSession struct {
+	precisions map[string]krakenPrecision
+}
+
+func (s *krakenSession) Decode(message []byte) ([]streamEvent, error) {
+	var msg krakenMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return nil, fmt.Errorf("invalid kraken message: %w", err)
+	}
+
+	if msg.Success != nil && !*msg.Success {
+		return nil, errors.New("kraken " + msg.Method + " failed: " + msg.Error)
+	}
+
+	switch msg.Channel {
+	case "heartbeat":
+		return []streamEvent{{kind: eventHeartbeat}}, nil
+	case "instrument":
+		var instruments krakenInstruments
+		if err := json.Unmarshal(msg.Data, &instruments); err != nil {
+			return nil, fmt.Errorf("invalid kraken instruments: %w", err)
+		}
+		for _, pair := range instruments.Pairs {
+			s.precisions[pair.Symbol] = krakenPrecision{price: pair.PricePrecision, qty: pair.QtyPrecision}
+		}
+		return nil, nil
+	case "book":
+	default:
+		return nil, nil
+	}
+
+	var entries []krakenBookEntry
+	if err := json.Unmarshal(msg.Data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid kraken book: %w", err)
+	}
+
+	kind := eventUpdate
+	if msg.Type == "snapshot" {
+		kind = eventSnapshot
+	}
+
+	events := make([]streamEvent, 0, len(entries))
+	for _, entry := range entries {
+		event := streamEvent{
+			kind: kind,
+			bids: krakenLevels(entry.Bids),
+			asks: krakenLevels(entry.Asks),
+		}
+		// Checksums can only be verified once the pair's precisions are
+		// known
+		if precision, ok := s.precisions[entry.Symbol]; ok {
+			event.checksum = &bookChecksum{
+				expected: entry.Checksum,
+				levels:   krakenChecksumDepth,
+				compute:  precision.checksum,
+			}
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// checksum returns Kraken's CRC32 of the top of a book: each ask from the
+// best up, then each bid from the best down, as price then quantity with
+// the decimal point and leading zeros removed
+func (p krakenPrecision) checksum(bids, asks []Level) uint32 {
+	var b strings.Builder
+	for _, side := range [][]Level{asks, bids} {
+		for _, level := range side {
+			b.WriteString(krakenChecksumField(level.Price, p.price))
+			b.WriteString(krakenChecksumField(level.Size, p.qty))
+		}
+	}
+	return crc32.ChecksumIEEE([]byte(b.String()))
+}
+
+// krakenChecksumField formats a value for the book checksum
+func krakenChecksumField(value float64, precision int) string {
+	formatted := strconv.FormatFloat(value, 'f', precision, 64)
+	return strings.TrimLeft(strings.Replace(formatted, ".", "", 1), "0")
+}
+
+func krakenLevels(raw []krakenLevel) []Level {
+	levels := make([]Level, len(raw))
+	for i, level := range raw {
+		levels[i] = Level{Price: level.Price, Size: level.Qty}
+	}
+	return levels
+}
@@ -0,0 +1,90 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"testing"
+	"time"
+)
+
+func TestKrakenChecksum(t *testing.T) {
+	precision := krakenPrecision{price: 1, qty: 8}
+	bids := []Level{{Price: 100.0, Size: 0.5}}
+	asks := []Level{{Price: 100.5, Size: 1.25}}
+
+	// Asks then bids, price then quantity, without points or leading zeros
+	want := crc32.ChecksumIEEE([]byte("1005" + "125000000" + "1000" + "50000000"))
+	if got := precision.checksum(bids, asks); got != want {
+		t.Errorf("checksum = %d, want %d", got, want)
+	}
+}
+
+// krakenBook returns a book message for ETH/USD with a single level per side
+func krakenBook(kind string, bid, ask Level, checksum uint32) []byte {
+	return []byte(fmt.Sprintf(`{"channel":"book","type":%q,"data":[{"symbol":"ETH/USD",`+
+		`"bids":[{"price":%v,"qty":%v}],"asks":[{"price":%v,"qty":%v}],"checksum":%d}]}`,
+		kind, bid.Price, bid.Size, ask.Price, ask.Size, checksum))
+}
+
+func TestKrakenStreamDesyncsOnChecksumMismatch(t *testing.T) {
+	stream, err := NewStream(NewKraken(fixtureClient()), StreamConfig{Markets: []Market{ethUSD}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates, unsubscribe := stream.SubscribeBooks(0, 0)
+	defer unsubscribe()
+
+	ctx := context.Background()
+	session := stream.protocol.NewSession()
+	handle := func(message []byte) error {
+		t.Helper()
+		events, err := session.Decode(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range events {
+			if err := stream.handle(ctx, ethUSD, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	next := func() BookUpdate {
+		t.Helper()
+		select {
+		case update := <-updates:
+			return update
+		case <-time.After(time.Second):
+			t.Fatal("no book update")
+			return BookUpdate{}
+		}
+	}
+
+	instrument := `{"channel":"instrument","type":"snapshot","data":{"pairs":[{"symbol":"ETH/USD","price_precision":1,"qty_precision":8}]}}`
+	if err := handle([]byte(instrument)); err != nil {
+		t.Fatal(err)
+	}
+
+	bid, ask := Level{Price: 100.0, Size: 0.5}, Level{Price: 100.5, Size: 1.25}
+	checksum := krakenPrecision{price: 1, qty: 8}.checksum([]Level{bid}, []Level{ask})
+	if err := handle(krakenBook("snapshot", bid, ask, checksum)); err != nil {
+		t.Fatalf("snapshot with a matching checksum: %v", err)
+	}
+	if update := next(); update.Book == nil || update.Book.Bids[0] != bid || update.Book.Asks[0] != ask {
+		t.Fatalf("update after snapshot = %+v, want the synced book", update.Book)
+	}
+
+	// An update whose checksum does not match the applied book
+	bid.Size = 0.75
+	if err := handle(krakenBook("update", bid, ask, checksum)); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("handle() = %v, want %v", err, ErrChecksumMismatch)
+	}
+	if _, ok := stream.OrderBook(ethUSD, 10); ok {
+		t.Error("book is still synced after a checksum mismatch")
+	}
+	if update := next(); update.Book != nil {
+		t.Errorf("update after mismatch carries a book, want none while out of sync")
+	}
+}
@@ -0,0 +1,110 @@
+package cex
+
+import (
+	"sync"
+	"time"
+
+	"github.com/arbie-buckets/constants"
+)
+
+// BookUpdate is the state of a streamed book after it changed
+type BookUpdate struct {
+	Exchange constants.Exchange
+	Market   Market
+
+	// Book is a copy of the local book, or nil while it is out of sync
+	Book *OrderBook
+
+	// MaxAge is the age after which the stream considers the book stale
+	MaxAge time.Duration
+}
+
+// Stale reports whether the update carries no book or one older than its
+// maximum age
+func (u BookUpdate) Stale() bool {
+	return u.Book == nil || time.Since(u.Book.Timestamp) > u.MaxAge
+}
+
+// bookKey identifies a book across exchanges
+type bookKey struct {
+	exchange constants.Exchange
+	market   Market
+}
+
+// bookSubscriber delivers book updates to its channel. Updates are queued
+// while the receiver is behind and replaced by newer updates for the same
+// book, so the latest state of every book is always delivered.
+type bookSubscriber struct {
+	ch    chan BookUpdate
+	depth int
+	wake  chan struct{}
+	stop  chan struct{}
+
+	mutex    sync.Mutex
+	order    []bookKey
+	pending  map[bookKey]BookUpdate
+	stopOnce sync.Once
+}
+
+// newBookSubscriber starts delivering books of up to depth levels per side
+// to a channel with the given buffer
+func newBookSubscriber(depth, buffer int) *bookSubscriber {
+	s := &bookSubscriber{
+		ch:      make(chan BookUpdate, max(buffer, 0)),
+		depth:   depth,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		pending: make(map[bookKey]BookUpdate),
+	}
+	go s.run()
+	return s
+}
+
+// publish queues an update, replacing any undelivered one for the same book
+func (s *bookSubscriber) publish(update BookUpdate) {
+	key := bookKey{update.Exchange, update.Market}
+
+	s.mutex.Lock()
+	if _, queued := s.pending[key]; !queued {
+		s.order = append(s.order, key)
+	}
+	s.pending[key] = update
+	s.mutex.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// cancel closes the channel, dropping undelivered updates
+func (s *bookSubscriber) cancel() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+func (s *bookSubscriber) run() {
+	defer close(s.ch)
+	for {
+		s.mutex.Lock()
+		if len(s.order) == 0 {
+			s.mutex.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.stop:
+				return
+			}
+		}
+		key := s.order[0]
+		s.order = s.order[1:]
+		next := s.pending[key]
+		delete(s.pending, key)
+		s.mutex.Unlock()
+
+		select {
+		case s.ch <- next:
+		case <-s.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/arbie-buckets/constants"
+	"github.com/arbie-buckets/venue"
+)
+
+// DefaultBookDepth is the number of levels per side fetched for quotes
+const DefaultBookDepth = 50
+
+// ErrInsufficientDepth is returned when a book cannot fill a quote
+var ErrInsufficientDepth = errors.New("order book too shallow for quote")
+
+// Venue adapts a connector to the venue.Venue interface so CEX markets can
+// be priced alongside DEX pools
+type Venue struct {
+	connector Connector
+	depth     int
+}
+
+// NewVenue creates a venue backed by connector's order books
+func NewVenue(connector Connector, depth int) *Venue {
+	if depth <= 0 {
+		depth = DefaultBookDepth
+	}
+	return &Venue{connector: connector, depth: depth}
+}
+
+// RegisterVenues makes every CEX connector available to venue configuration
+// under its exchange identifier
+func RegisterVenues() {
+	for _, exchange := range constants.Exchanges {
+		exchange := exchange
+		venue.RegisterBuilder(exchange.String(), func(deps venue.Deps) (venue.Venue, error) {
+			if deps.HTTPClient == nil {
+				return nil, errors.New("HTTP client not configured")
+			}
+			connector, err := NewConnector(exchange, deps.HTTPClient)
+			if err != nil {
+				return nil, err
+			}
+			return NewVenue(connector, DefaultBookDepth), nil
+		})
+	}
+}
+
+// Connector returns the underlying REST connector
+func (v *Venue) Connector() Connector { return v.connector }
+
+// ID returns the exchange identifier
+func (v *Venue) ID() string { return v.connector.Exchange().String() }
+
+// Name returns the exchange display name
+func (v *Venue) Name() string {
+	id := v.ID()
+	return strings.ToUpper(id[:1]) + id[1:]
+}
+
+// Kind returns venue.KindCEX
+func (v *Venue) Kind() venue.Kind { return venue.KindCEX }
+
+// SupportsPair reports whether both tokens map to a listed market
+func (v *Venue) SupportsPair(pair venue.Pair) bool {
+	_, _, ok := marketForPair(pair)
+	return ok
+}
+
+// Ping fetches the ticker of the first listed market
+func (v *Venue) Ping(ctx context.Context) error {
+	_, err := v.connector.Ticker(ctx, Markets[0])
+	return err
+}
+
+// Quote walks the order book to fill amountIn, net of the taker fee
+func (v *Venue) Quote(ctx context.Context, pair venue.Pair, amountIn *big.Int) (*venue.Quote, error) {
+	market, inverted, ok := marketForPair(pair)
+	if !ok {
+		return nil, venue.ErrUnsupportedPair
+	}
+
+	book, err := v.connector.OrderBook(ctx, market, v.depth)
+	if err != nil {
+		return nil, err
+	}
+
+	size := toUnits(amountIn, pair.Base.Decimals)
+	var (
+		received float64
+		filled   bool
+	)
+	if inverted {
+		received, filled = book.BuyBase(size)
+	} else {
+		received, filled = book.SellBase(size)
+	}
+	if !filled {
+		return nil, fmt.Errorf("%w: %s on %s", ErrInsufficientDepth, pair, v.ID())
+	}
+
+	fee := v.connector.TakerFeeBps()
+	received *= 1 - float64(fee)/10000
+
+	return &venue.Quote{
+		VenueID:   v.ID(),
+		Pair:      pair,
+		AmountIn:  new(big.Int).Set(amountIn),
+		AmountOut: fromUnits(received, pair.Quote.Decimals),
+		FeeBps:    fee,
+		Timestamp: book.Timestamp,
+	}, nil
+}
+
+// Liquidity reports the book depth available on the side the pair trades
+// against, expressed in raw token units
+func (v *Venue) Liquidity(ctx context.Context, pair venue.Pair) (*venue.Liquidity, error) {
+	market, inverted, ok := marketForPair(pair)
+	if !ok {
+		return nil, venue.ErrUnsupportedPair
+	}
+
+	book, err := v.connector.OrderBook(ctx, market, v.depth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Selling the base asset hits bids; buying it lifts asks
+	var baseReserve, quoteReserve float64
+	if inverted {
+		size, notional := book.AskDepth()
+		baseReserve, quoteReserve = notional, size
+	} else {
+		size, notional := book.BidDepth()
+		baseReserve, quoteReserve = size, notional
+	}
+
+	return &venue.Liquidity{
+		VenueID:      v.ID(),
+		Pair:         pair,
+		BaseReserve:  fromUnits(baseReserve, pair.Base.Decimals),
+		QuoteReserve: fromUnits(quoteReserve, pair.Quote.Decimals),
+		Timestamp:    book.Timestamp,
+	}, nil
+}
+
+// marketForPair maps a token pair onto a listed market, reporting whether
+// the pair sells the market's quote asset
+func marketForPair(pair venue.Pair) (Market, bool, bool) {
+	base, ok := TokenAssets[strings.ToUpper(pair.Base.Symbol)]
+	if !ok {
+		return Market{}, false, false
+	}
+	quote, ok := TokenAssets[strings.ToUpper(pair.Quote.Symbol)]
+	if !ok {
+		return Market{}, false, false
+	}
+	return MarketForAssets(base, quote)
+}
+
+// toUnits converts a raw token amount to whole units
+func toUnits(amount *big.Int, decimals uint8) float64 {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(math.Pow10(int(decimals)))).Float64()
+	return value
+}
+
+// fromUnits converts whole units to a raw token amount
+func fromUnits(value float64, decimals uint8) *big.Int {
+	amount, _ := new(big.Float).Mul(big.NewFloat(value), big.NewFloat(math.Pow10(int(decimals)))).Int(nil)
+	return amount
+}
@@ -0,0 +1,44 @@
+// Command signer serves a keystore account over a Clef-compatible JSON-RPC
+// API, standing in for Clef when running against wallet.signerUrl locally.
+// It approves every request, so it must only listen on a trusted interface.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/arbie-buckets/blockchain/signer"
+)
+
+func main() {
+	keystorePath := flag.String("keystore", "", "keystore file of the account to sign for")
+	passphraseFile := flag.String("passphrase-file", "", "file holding the keystore passphrase (prompts when empty)")
+	listen := flag.String("listen", "127.0.0.1:8550", "address to serve the signer API on")
+	flag.Parse()
+
+	if *keystorePath == "" {
+		log.Fatal("-keystore is required")
+	}
+
+	passphrase, err := signer.ReadPassphrase(*passphraseFile, *keystorePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	wallet, err := signer.NewKeystoreSigner(*keystorePath, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer wallet.Close()
+
+	server, err := signer.NewServer(wallet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer server.Stop()
+
+	log.Printf("Signing for %s on http://%s", wallet.Address().Hex(), *listen)
+	if err := http.ListenAndServe(*listen, server); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration written as a string such as "30s" in config
+// files
+type Duration time.Duration
+
+// UnmarshalText parses a duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Secret is a string that is redacted when the config is printed
+type Secret string
+
+// Profile holds the defaults of a network
+type Profile struct {
+	RPCURL  string
+	ChainID uint64
+}
+
+// Network profiles
+const (
+	NetworkBaseMainnet = "base-mainnet"
+	NetworkBaseSepolia = "base-sepolia"
+	NetworkAnvil       = "anvil"
+	NetworkSimulated   = "simulated"
+)
+
+// DefaultNetwork is used when no network is configured
+const DefaultNetwork = NetworkBaseSepolia
+
+// SimulatedRPCURL selects the in-memory simulated backend in place of a
+// node URL
+const SimulatedRPCURL = "simulated"
+
+// Profiles maps network names to their defaults
+var Profiles = map[string]Profile{
+	NetworkBaseMainnet: {RPCURL: "https://mainnet.base.org", ChainID: 8453},
+	NetworkBaseSepolia: {RPCURL: "https://sepolia.base.org", ChainID: 84532},
+	NetworkAnvil:       {RPCURL: "http://127.0.0.1:8545", ChainID: 31337},
+	NetworkSimulated:   {RPCURL: SimulatedRPCURL, ChainID: 1337},
+}
+
+// Networks returns the profile names in alphabetical order
+func Networks() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Config is the backend's configuration. Zero values of optional settings
+// leave the owning package's default in place.
+type Config struct {
+	// Network selects the profile chain defaults are taken from
+	Network string `yaml:"network" toml:"network"`
+
+	Server Server `yaml:"server" toml:"server"`
+	Chain  Chain  `yaml:"chain" toml:"chain"`
+
+	// Chains are run alongside Chain, each by its own blockchain service
+	Chains []AdditionalChain `yaml:"chains" toml:"chains"`
+
+	Wallet  Wallet  `yaml:"wallet" toml:"wallet"`
+	RPC     RPC     `yaml:"rpc" toml:"rpc"`
+	Venues  Venues  `yaml:"venues" toml:"venues"`
+	Prices  Prices  `yaml:"prices" toml:"prices"`
+	Trading Trading `yaml:"trading" toml:"trading"`
+	Stream  Stream  `yaml:"stream" toml:"stream"`
+}
+
+// Server configures the HTTP server
+type Server struct {
+	Port            string   `yaml:"port" toml:"port"`
+	CORSOrigins     []string `yaml:"corsOrigins" toml:"corsOrigins"`
+	ShutdownTimeout Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout"`
+
+	// AdminToken authorizes admin endpoints; without it they only accept
+	// requests from localhost
+	AdminToken Secret `yaml:"adminToken" toml:"adminToken"`
+}
+
+// Chain configures the chain and the arbitrage contract
+type Chain struct {
+	RPCURL string `yaml:"rpcUrl" toml:"rpcUrl" redact:"url"`
+
+	// ChainID is the chain the endpoint must serve
+	ChainID uint64 `yaml:"chainId" toml:"chainId"`
+
+	ContractAddress string `yaml:"contractAddress" toml:"contractAddress"`
+}
+
+// AdditionalChain configures a chain besides the primary one. Network
+// selects the profile an unset rpcUrl and chainId are taken from.
+type AdditionalChain struct {
+	Network string `yaml:"network" toml:"network"`
+	Chain   `yaml:",inline"`
+}
+
+// applyProfile fills the unset endpoint and chain ID from the network's
+// profile
+func (c *AdditionalChain) applyProfile() {
+	profile, ok := Profiles[c.Network]
+	if !ok {
+		return
+	}
+	if c.RPCURL == "" {
+		c.RPCURL = profile.RPCURL
+	}
+	if c.ChainID == 0 {
+		c.ChainID = profile.ChainID
+	}
+}
+
+// Wallet configures how trades are signed. Only one backend may be set.
+type Wallet struct {
+	// Keystore is an encrypted go-ethereum keystore JSON file. Its
+	// passphrase is read from PassphraseFile, or prompted for on a terminal.
+	Keystore       string `yaml:"keystore" toml:"keystore"`
+	PassphraseFile string `yaml:"passphraseFile" toml:"passphraseFile"`
+
+	// SignerURL is a Clef-compatible remote signer. Account selects one of
+	// its accounts; empty uses the first.
+	SignerURL string `yaml:"signerUrl" toml:"signerUrl" redact:"url"`
+	Account   string `yaml:"account" toml:"account"`
+
+	// PrivateKey is a raw hex key, for development only
+	PrivateKey Secret `yaml:"privateKey" toml:"privateKey"`
+}
+
+// RPC configures reconnects, deadlines and recorded fixtures
+type RPC struct {
+	InitialBackoff   Duration `yaml:"initialBackoff" toml:"initialBackoff"`
+	MaxBackoff       Duration `yaml:"maxBackoff" toml:"maxBackoff"`
+	FailureThreshold int      `yaml:"failureThreshold" toml:"failureThreshold"`
+	BreakerCooldown  Duration `yaml:"breakerCooldown" toml:"breakerCooldown"`
+
+	CallTimeout Duration `yaml:"callTimeout" toml:"callTimeout"`
+	SendTimeout Duration `yaml:"sendTimeout" toml:"sendTimeout"`
+	WaitTimeout Duration `yaml:"waitTimeout" toml:"waitTimeout"`
+
+	FixturesDir     string   `yaml:"fixturesDir" toml:"fixturesDir"`
+	RecordFixtures  bool     `yaml:"recordFixtures" toml:"recordFixtures"`
+	ReplayLatency   Duration `yaml:"replayLatency" toml:"replayLatency"`
+	ReplayErrorRate float64  `yaml:"replayErrorRate" toml:"replayErrorRate"`
+}
+
+// Venues configures the trading venues and CEX market data
+type Venues struct {
+	// Enabled lists venue IDs; empty enables the default venues
+	Enabled []string `yaml:"enabled" toml:"enabled"`
+
+	CEXStreaming      bool     `yaml:"cexStreaming" toml:"cexStreaming"`
+	CEXMaxBookAge     Duration `yaml:"cexMaxBookAge" toml:"cexMaxBookAge"`
+	CEXFixturesDir    string   `yaml:"cexFixturesDir" toml:"cexFixturesDir"`
+	CEXRecordFixtures bool     `yaml:"cexRecordFixtures" toml:"cexRecordFixtures"`
+	CEXDEXMode        bool     `yaml:"cexDexMode" toml:"cexDexMode"`
+}
+
+// Prices configures the price oracle and history
+type Prices struct {
+	CoinGeckoAPIKey Secret `yaml:"coingeckoApiKey" toml:"coingeckoApiKey"`
+
+	// ChainlinkFeeds overrides feeds as SYMBOL=ADDRESS:HEARTBEAT entries
+	ChainlinkFeeds []string `yaml:"chainlinkFeeds" toml:"chainlinkFeeds"`
+
+	MaxDeviation   float64  `yaml:"maxDeviation" toml:"maxDeviation"`
+	MaxAge         Duration `yaml:"maxAge" toml:"maxAge"`
+	HistoryFile    string   `yaml:"historyFile" toml:"historyFile"`
+	SampleInterval Duration `yaml:"sampleInterval" toml:"sampleInterval"`
+}
+
+// Trading configures route search, sizing, slippage and execution
+type Trading struct {
+	// Tokens lists the symbols scanned for opportunities; empty scans every
+	// known token
+	Tokens []string `yaml:"tokens" toml:"tokens"`
+
+	RouteMaxHops         int     `yaml:"routeMaxHops" toml:"routeMaxHops"`
+	MaxTradeUSD          float64 `yaml:"maxTradeUsd" toml:"maxTradeUsd"`
+	MaxPoolShare         float64 `yaml:"maxPoolShare" toml:"maxPoolShare"`
+	SlippageToleranceBps uint32  `yaml:"slippageToleranceBps" toml:"slippageToleranceBps"`
+	MaxQuoteAgeBlocks    uint64  `yaml:"maxQuoteAgeBlocks" toml:"maxQuoteAgeBlocks"`
+	MinSpreadBps         float64 `yaml:"minSpreadBps" toml:"minSpreadBps"`
+	MinSpreadProfitUSD   float64 `yaml:"minSpreadProfitUsd" toml:"minSpreadProfitUsd"`
+	PendingTradesFile    string  `yaml:"pendingTradesFile" toml:"pendingTradesFile"`
+}
+
+// Stream configures the event stream
+type Stream struct {
+	OpportunityInterval Duration `yaml:"opportunityInterval" toml:"opportunityInterval"`
+}
+
+// Default returns the configuration used before a file and the environment
+// are applied, on the default network
+func Default() *Config {
+	c := &Config{
+		Server: Server{
+			Port:            "8080",
+			CORSOrigins:     []string{"http://localhost:3000"},
+			ShutdownTimeout: Duration(30 * time.Second),
+		},
+	}
+	c.applyProfile(DefaultNetwork)
+	return c
+}
+
+// applyProfile switches to a network and takes its chain defaults
+func (c *Config) applyProfile(network string) {
+	c.Network = network
+	if profile, ok := Profiles[network]; ok {
+		c.Chain.RPCURL = profile.RPCURL
+		c.Chain.ChainID = profile.ChainID
+	}
+}
+
+// Redacted returns a copy of the config with secrets masked, for printing
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.Server.CORSOrigins = append([]string(nil), c.Server.CORSOrigins...)
+	redacted.Chains = append([]AdditionalChain(nil), c.Chains...)
+	redacted.Venues.Enabled = append([]string(nil), c.Venues.Enabled...)
+	redacted.Prices.ChainlinkFeeds = append([]string(nil), c.Prices.ChainlinkFeeds...)
+	redacted.Trading.Tokens = append([]string(nil), c.Trading.Tokens...)
+
+	redacted.Server.AdminToken = redact(c.Server.AdminToken)
+	redacted.Wallet.PrivateKey = redact(c.Wallet.PrivateKey)
+	redacted.Prices.CoinGeckoAPIKey = redact(c.Prices.CoinGeckoAPIKey)
+
+	// Endpoint URLs often carry API keys
+	redacted.Chain.RPCURL = RedactURL(c.Chain.RPCURL)
+	for i := range redacted.Chains {
+		redacted.Chains[i].RPCURL = RedactURL(c.Chains[i].RPCURL)
+	}
+	redacted.Wallet.SignerURL = RedactURL(c.Wallet.SignerURL)
+	return &redacted
+}
+
+// String formats the redacted config as YAML
+func (c *Config) String() string {
+	data, err := encodeYAML(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("config: %v", err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// redact masks a set secret
+func redact(secret Secret) Secret {
+	if secret == "" {
+		return ""
+	}
+	return "<redacted>"
+}
+
+// RedactURL masks the userinfo, path and query of an endpoint URL, where
+// providers put API keys, keeping its scheme and host
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		if raw == "" || raw == SimulatedRPCURL {
+			return raw
+		}
+		return "<redacted>"
+	}
+
+	masked := u.Scheme + "://"
+	if u.User != nil {
+		masked += "<redacted>@"
+	}
+	masked += u.Host
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" {
+		masked += "/<redacted>"
+	}
+	return masked
+}
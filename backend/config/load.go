@@ -0,0 +1,290 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration: defaults, then the network profile, then
+// the file at path (YAML or TOML by extension; none when empty), then
+// environment variables, including those in a .env file. The result is
+// validated.
+func Load(path string) (*Config, error) {
+	// Variables already set take precedence over .env
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	c, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parse builds the configuration like Load without validating it
+func parse(path string) (*Config, error) {
+	var data []byte
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	// The network is read first so the file and environment override its
+	// profile rather than the other way round
+	var selected struct {
+		Network string `yaml:"network" toml:"network"`
+	}
+	if err := decode(path, data, &selected); err != nil {
+		return nil, err
+	}
+	network := DefaultNetwork
+	if selected.Network != "" {
+		network = selected.Network
+	}
+	if env := os.Getenv("NETWORK"); env != "" {
+		network = env
+	}
+
+	c := Default()
+	c.applyProfile(network)
+	if err := decode(path, data, c); err != nil {
+		return nil, err
+	}
+	c.Network = network
+
+	if err := c.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	// The simulated backend runs its own chain whatever the profile
+	if c.Chain.RPCURL == SimulatedRPCURL {
+		c.Chain.ChainID = Profiles[NetworkSimulated].ChainID
+	}
+	for i := range c.Chains {
+		c.Chains[i].applyProfile()
+		if c.Chains[i].RPCURL == SimulatedRPCURL {
+			c.Chains[i].ChainID = Profiles[NetworkSimulated].ChainID
+		}
+	}
+	return c, nil
+}
+
+// decode parses a config file by its extension
+func decode(path string, data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, v)
+	case ".toml":
+		err = toml.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("unsupported config file %s: use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// encodeYAML formats v as YAML with two-space indentation
+func encodeYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), encoder.Close()
+}
+
+// envBinding overrides a setting from an environment variable
+type envBinding struct {
+	name  string
+	apply func(c *Config, value string) error
+}
+
+// envBindings lists the variables that override the file. The names are the
+// ones the backend has always read.
+var envBindings = []envBinding{
+	{"PORT", setString(func(c *Config) *string { return &c.Server.Port })},
+	{"CORS_ORIGINS", setList(func(c *Config) *[]string { return &c.Server.CORSOrigins })},
+	{"SHUTDOWN_TIMEOUT", setDuration(func(c *Config) *Duration { return &c.Server.ShutdownTimeout })},
+	{"ADMIN_TOKEN", setSecret(func(c *Config) *Secret { return &c.Server.AdminToken })},
+
+	{"BASE_TESTNET_RPC_URL", setString(func(c *Config) *string { return &c.Chain.RPCURL })},
+	{"CHAIN_ID", setUint(func(c *Config) *uint64 { return &c.Chain.ChainID })},
+	{"ARBITRAGE_CONTRACT_ADDRESS", setString(func(c *Config) *string { return &c.Chain.ContractAddress })},
+	{"KEYSTORE_FILE", setString(func(c *Config) *string { return &c.Wallet.Keystore })},
+	{"KEYSTORE_PASSPHRASE_FILE", setString(func(c *Config) *string { return &c.Wallet.PassphraseFile })},
+	{"SIGNER_URL", setString(func(c *Config) *string { return &c.Wallet.SignerURL })},
+	{"SIGNER_ACCOUNT", setString(func(c *Config) *string { return &c.Wallet.Account })},
+	{"TEST_WALLET_PK_1", setSecret(func(c *Config) *Secret { return &c.Wallet.PrivateKey })},
+
+	{"RPC_INITIAL_BACKOFF", setDuration(func(c *Config) *Duration { return &c.RPC.InitialBackoff })},
+	{"RPC_MAX_BACKOFF", setDuration(func(c *Config) *Duration { return &c.RPC.MaxBackoff })},
+	{"RPC_FAILURE_THRESHOLD", setInt(func(c *Config) *int { return &c.RPC.FailureThreshold })},
+	{"RPC_BREAKER_COOLDOWN", setDuration(func(c *Config) *Duration { return &c.RPC.BreakerCooldown })},
+	{"RPC_CALL_TIMEOUT", setDuration(func(c *Config) *Duration { return &c.RPC.CallTimeout })},
+	{"RPC_SEND_TIMEOUT", setDuration(func(c *Config) *Duration { return &c.RPC.SendTimeout })},
+	{"RPC_WAIT_TIMEOUT", setDuration(func(c *Config) *Duration { return &c.RPC.WaitTimeout })},
+	{"RPC_FIXTURES_DIR", setString(func(c *Config) *string { return &c.RPC.FixturesDir })},
+	{"RPC_RECORD_FIXTURES", setBool(func(c *Config) *bool { return &c.RPC.RecordFixtures })},
+	{"RPC_REPLAY_LATENCY", setDuration(func(c *Config) *Duration { return &c.RPC.ReplayLatency })},
+	{"RPC_REPLAY_ERROR_RATE", setFloat(func(c *Config) *float64 { return &c.RPC.ReplayErrorRate })},
+
+	{"ENABLED_VENUES", setList(func(c *Config) *[]string { return &c.Venues.Enabled })},
+	{"CEX_STREAMING", setBool(func(c *Config) *bool { return &c.Venues.CEXStreaming })},
+	{"CEX_MAX_BOOK_AGE", setDuration(func(c *Config) *Duration { return &c.Venues.CEXMaxBookAge })},
+	{"CEX_FIXTURES_DIR", setString(func(c *Config) *string { return &c.Venues.CEXFixturesDir })},
+	{"CEX_RECORD_FIXTURES", setBool(func(c *Config) *bool { return &c.Venues.CEXRecordFixtures })},
+	{"CEX_DEX_MODE", setBool(func(c *Config) *bool { return &c.Venues.CEXDEXMode })},
+
+	{"COINGECKO_API_KEY", setSecret(func(c *Config) *Secret { return &c.Prices.CoinGeckoAPIKey })},
+	{"CHAINLINK_FEEDS", setList(func(c *Config) *[]string { return &c.Prices.ChainlinkFeeds })},
+	{"PRICE_MAX_DEVIATION", setFloat(func(c *Config) *float64 { return &c.Prices.MaxDeviation })},
+	{"PRICE_MAX_AGE", setDuration(func(c *Config) *Duration { return &c.Prices.MaxAge })},
+	{"PRICE_HISTORY_FILE", setString(func(c *Config) *string { return &c.Prices.HistoryFile })},
+	{"PRICE_SAMPLE_INTERVAL", setDuration(func(c *Config) *Duration { return &c.Prices.SampleInterval })},
+
+	{"SCAN_TOKENS", setList(func(c *Config) *[]string { return &c.Trading.Tokens })},
+	{"ROUTE_MAX_HOPS", setInt(func(c *Config) *int { return &c.Trading.RouteMaxHops })},
+	{"MAX_TRADE_USD", setFloat(func(c *Config) *float64 { return &c.Trading.MaxTradeUSD })},
+	{"MAX_POOL_SHARE", setFloat(func(c *Config) *float64 { return &c.Trading.MaxPoolShare })},
+	{"SLIPPAGE_TOLERANCE_BPS", setUint32(func(c *Config) *uint32 { return &c.Trading.SlippageToleranceBps })},
+	{"MAX_QUOTE_AGE_BLOCKS", setUint(func(c *Config) *uint64 { return &c.Trading.MaxQuoteAgeBlocks })},
+	{"MIN_SPREAD_BPS", setFloat(func(c *Config) *float64 { return &c.Trading.MinSpreadBps })},
+	{"MIN_SPREAD_PROFIT_USD", setFloat(func(c *Config) *float64 { return &c.Trading.MinSpreadProfitUSD })},
+	{"PENDING_TRADES_FILE", setString(func(c *Config) *string { return &c.Trading.PendingTradesFile })},
+
+	{"STREAM_OPPORTUNITY_INTERVAL", setDuration(func(c *Config) *Duration { return &c.Stream.OpportunityInterval })},
+}
+
+// applyEnv applies every set, non-empty environment variable
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, binding := range envBindings {
+		value, ok := lookup(binding.name)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		if err := binding.apply(c, strings.TrimSpace(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", binding.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setString(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+func setSecret(field func(*Config) *Secret) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = Secret(value)
+		return nil
+	}
+}
+
+// setList splits a comma separated list
+func setList(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*field(c) = items
+		return nil
+	}
+}
+
+func setBool(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func setInt(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func setUint(field func(*Config) *uint64) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func setUint32(field func(*Config) *uint32) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return err
+		}
+		*field(c) = uint32(parsed)
+		return nil
+	}
+}
+
+func setFloat(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}
+}
+
+func setDuration(field func(*Config) *Duration) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = Duration(parsed)
+		return nil
+	}
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultWatchInterval is how often the config file is checked for changes
+const DefaultWatchInterval = 2 * time.Second
+
+// restartOnly lists the settings, by path prefix, that are read once at
+// startup. Reloads changing them are rejected.
+var restartOnly = []string{
+	"network",
+	"server.port",
+	"server.corsOrigins",
+	"chain.chainId",
+	"chain.contractAddress",
+	"chains",
+	"wallet.",
+	"rpc.fixturesDir",
+	"rpc.recordFixtures",
+	"rpc.replay",
+	"venues.cex",
+	"prices.",
+	"trading.pendingTradesFile",
+	"stream.",
+}
+
+// reloadable lists settings under a restartOnly prefix that still apply
+// without a restart. List entries are matched without their index, so
+// chains[].rpcUrl covers the endpoint of every additional chain.
+var reloadable = []string{
+	"chains[].rpcUrl",
+}
+
+// listIndex matches the index of a list entry in a setting's path
+var listIndex = regexp.MustCompile(`\[\d+\]`)
+
+// Change is a setting that differs between two configs. Secrets are shown
+// as "<redacted>", and endpoint URLs with their userinfo, path and query
+// redacted.
+type Change struct {
+	Path string `json:"path"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// String formats the change as path: old -> new
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Path, c.Old, c.New)
+}
+
+// Diff returns the settings that differ between old and new, by path
+func Diff(old, new *Config) []Change {
+	before, after := flatten(old), flatten(new)
+
+	var changes []Change
+	for path, value := range after {
+		if before[path] != value {
+			changes = append(changes, Change{Path: path, Old: before[path], New: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// flatten maps every setting's path to its formatted value. Set secrets,
+// and the redacted parts of endpoint URLs, are replaced by a short hash so
+// that changes show up without the value. Each entry of a list of sections
+// is flattened under its index, e.g. chains[0].rpcUrl.
+func flatten(c *Config) map[string]string {
+	values := make(map[string]string)
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			value := v.Field(i)
+			if options == "inline" {
+				walk(prefix, value)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			path := prefix + name
+
+			if field.Tag.Get("redact") == "url" {
+				values[path] = RedactURL(value.String())
+				if values[path] != value.String() {
+					values[path] += " " + shortHash(value.String())
+				}
+				continue
+			}
+
+			switch value := value.Interface().(type) {
+			case Secret:
+				values[path] = string(redact(value))
+				if value != "" {
+					values[path] += " " + shortHash(string(value))
+				}
+				continue
+			case Duration:
+				values[path] = time.Duration(value).String()
+				continue
+			case []string:
+				values[path] = strings.Join(value, ",")
+				continue
+			}
+
+			if value.Kind() == reflect.Struct {
+				walk(path+".", value)
+				continue
+			}
+			if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Struct {
+				for j := 0; j < value.Len(); j++ {
+					walk(fmt.Sprintf("%s[%d].", path, j), value.Index(j))
+				}
+				continue
+			}
+			values[path] = fmt.Sprint(value.Interface())
+		}
+	}
+	walk("", reflect.ValueOf(c).Elem())
+	return values
+}
+
+// shortHash identifies a hidden value without revealing it
+func shortHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Applier checks that a component can take a new config and returns a
+// function that switches it over. Appliers must not change anything until
+// commit is called, so that a reload either applies everywhere or nowhere.
+type Applier func(old, new *Config) (commit func(), err error)
+
+// ReloadResult describes a reload attempt
+type ReloadResult struct {
+	Source  string    `json:"source"`
+	At      time.Time `json:"at"`
+	Changes []Change  `json:"changes"`
+	Applied bool      `json:"applied"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Manager holds the current config and reloads it from its file
+type Manager struct {
+	path string
+
+	// mutex serializes reloads
+	mutex    sync.Mutex
+	current  atomic.Pointer[Config]
+	appliers []namedApplier
+}
+
+type namedApplier struct {
+	name  string
+	apply Applier
+}
+
+// NewManager creates a manager for the config loaded from path
+func NewManager(path string, initial *Config) *Manager {
+	m := &Manager{path: path}
+	m.current.Store(initial)
+	return m
+}
+
+// Current returns the config in effect
+func (m *Manager) Current() *Config {
+	return m.current.Load()
+}
+
+// OnReload registers a component to be reconfigured by reloads
+func (m *Manager) OnReload(name string, apply Applier) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.appliers = append(m.appliers, namedApplier{name: name, apply: apply})
+}
+
+// Reload reads the config again and applies it to every registered
+// component. A change is rejected, leaving everything as it was, when the
+// new config is invalid, touches a restart-only setting or a component
+// refuses it. source describes what triggered the reload for the audit log.
+func (m *Manager) Reload(source string) (*ReloadResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result := &ReloadResult{Source: source, At: time.Now()}
+	err := m.reload(result)
+	if err != nil {
+		// Joined errors are kept on one line for the log
+		result.Error = strings.ReplaceAll(err.Error(), "\n", "; ")
+	}
+	audit(result)
+	return result, err
+}
+
+func (m *Manager) reload(result *ReloadResult) error {
+	old := m.Current()
+	next, err := parse(m.path)
+	if err != nil {
+		return err
+	}
+
+	result.Changes = Diff(old, next)
+	if len(result.Changes) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, change := range result.Changes {
+		if isRestartOnly(change.Path) {
+			errs = append(errs, fmt.Errorf("%s: requires a restart", change.Path))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("rejected: %w", errors.Join(errs...))
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("rejected: %w", err)
+	}
+
+	// Every component must accept the change before any of them switches
+	commits := make([]func(), 0, len(m.appliers))
+	for _, applier := range m.appliers {
+		commit, err := applier.apply(old, next)
+		if err != nil {
+			return fmt.Errorf("rejected by %s: %w", applier.name, err)
+		}
+		if commit != nil {
+			commits = append(commits, commit)
+		}
+	}
+	for _, commit := range commits {
+		commit()
+	}
+
+	m.current.Store(next)
+	result.Applied = true
+	return nil
+}
+
+// Watch reloads the config whenever its file changes, until ctx is
+// cancelled
+func (m *Manager) Watch(ctx context.Context, interval time.Duration) {
+	if m.path == "" {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	last, _ := os.Stat(m.path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(m.path)
+		if err != nil {
+			continue
+		}
+		if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+
+		_, _ = m.Reload("file " + m.path)
+	}
+}
+
+// isRestartOnly reports whether the setting at path is read only at startup
+func isRestartOnly(path string) bool {
+	if slices.Contains(reloadable, listIndex.ReplaceAllString(path, "[]")) {
+		return false
+	}
+	for _, prefix := range restartOnly {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// audit logs the outcome of a reload
+func audit(result *ReloadResult) {
+	changes := make([]string, len(result.Changes))
+	for i, change := range result.Changes {
+		changes[i] = change.String()
+	}
+	summary := "no changes"
+	if len(changes) > 0 {
+		summary = strings.Join(changes, "; ")
+	}
+
+	switch {
+	case result.Applied:
+		log.Printf("Config reload from %s applied: %s", result.Source, summary)
+	case result.Error != "":
+		log.Printf("Warning: Config reload from %s failed: %s (changes: %s)", result.Source, result.Error, summary)
+	default:
+		log.Printf("Config reload from %s: %s", result.Source, summary)
+	}
+}
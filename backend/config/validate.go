@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Validate checks the config for values the backend cannot run with,
+// reporting every problem at once. That the endpoint serves ChainID can
+// only be checked once connected, which the blockchain service does.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	_, known := Profiles[c.Network]
+	check(known, "network: unknown network %q, expected one of %s", c.Network, strings.Join(Networks(), ", "))
+
+	// Server
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port <= 65535, "server.port: invalid port %q", c.Server.Port)
+	for _, origin := range c.Server.CORSOrigins {
+		check(validURL(origin, "http", "https"), "server.corsOrigins: invalid origin %q", origin)
+	}
+	check(c.Server.ShutdownTimeout > 0, "server.shutdownTimeout: must be positive")
+
+	// Chains, each served once
+	errs = append(errs, c.Chain.validate("chain")...)
+	seen := map[uint64]string{c.Chain.ChainID: "chain"}
+	for i, chain := range c.Chains {
+		prefix := fmt.Sprintf("chains[%d]", i)
+		if chain.Network != "" {
+			_, known := Profiles[chain.Network]
+			check(known, "%s.network: unknown network %q, expected one of %s", prefix, chain.Network, strings.Join(Networks(), ", "))
+		}
+		errs = append(errs, chain.validate(prefix)...)
+
+		if other, ok := seen[chain.ChainID]; ok && chain.ChainID > 0 {
+			check(false, "%s.chainId: chain %d is already configured by %s", prefix, chain.ChainID, other)
+		}
+		seen[chain.ChainID] = prefix
+	}
+
+	// Wallet; the key itself is never included in errors
+	backends := 0
+	for _, set := range []bool{c.Wallet.Keystore != "", c.Wallet.SignerURL != "", c.Wallet.PrivateKey != ""} {
+		if set {
+			backends++
+		}
+	}
+	check(backends <= 1, "wallet: set only one of keystore, signerUrl and privateKey")
+	check(c.Wallet.PassphraseFile == "" || c.Wallet.Keystore != "", "wallet.passphraseFile: requires wallet.keystore")
+	if c.Wallet.SignerURL != "" {
+		check(c.Wallet.SignerURL != SimulatedRPCURL && validRPCURL(c.Wallet.SignerURL), "wallet.signerUrl: invalid signer URL %q", RedactURL(c.Wallet.SignerURL))
+	}
+	if c.Wallet.Account != "" {
+		check(c.Wallet.SignerURL != "", "wallet.account: requires wallet.signerUrl")
+		check(common.IsHexAddress(c.Wallet.Account), "wallet.account: invalid address %q", c.Wallet.Account)
+	}
+	if c.Wallet.PrivateKey != "" {
+		_, err := crypto.HexToECDSA(strings.TrimPrefix(string(c.Wallet.PrivateKey), "0x"))
+		check(err == nil, "wallet.privateKey: invalid private key")
+
+		// Raw keys are for development; mainnet takes a keystore or signer
+		for _, chainID := range c.chainIDs() {
+			check(!mainnetChainIDs[chainID], "wallet.privateKey: raw keys are for development only, use wallet.keystore or wallet.signerUrl on chain %d", chainID)
+		}
+	}
+
+	// RPC
+	check(c.RPC.InitialBackoff >= 0 && c.RPC.MaxBackoff >= 0 && c.RPC.BreakerCooldown >= 0, "rpc: backoff and cooldown must not be negative")
+	check(c.RPC.FailureThreshold >= 0, "rpc.failureThreshold: must not be negative")
+	check(c.RPC.CallTimeout >= 0 && c.RPC.SendTimeout >= 0 && c.RPC.WaitTimeout >= 0, "rpc: timeouts must not be negative")
+	check(c.RPC.ReplayErrorRate >= 0 && c.RPC.ReplayErrorRate <= 1, "rpc.replayErrorRate: must be between 0 and 1")
+
+	// Prices
+	check(c.Prices.MaxDeviation >= 0, "prices.maxDeviation: must not be negative")
+	check(c.Prices.MaxAge >= 0 && c.Prices.SampleInterval >= 0, "prices: durations must not be negative")
+
+	// Trading
+	check(c.Trading.RouteMaxHops >= 0, "trading.routeMaxHops: must not be negative")
+	check(c.Trading.MaxTradeUSD >= 0, "trading.maxTradeUsd: must not be negative")
+	check(c.Trading.MaxPoolShare >= 0 && c.Trading.MaxPoolShare <= 1, "trading.maxPoolShare: must be between 0 and 1")
+	check(c.Trading.SlippageToleranceBps <= 10000, "trading.slippageToleranceBps: must be at most 10000")
+	check(c.Trading.MinSpreadProfitUSD >= 0, "trading.minSpreadProfitUsd: must not be negative")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// mainnetChainIDs are the production chains a raw private key may not be
+// used on
+var mainnetChainIDs = map[uint64]bool{
+	Profiles[NetworkBaseMainnet].ChainID: true,
+}
+
+// chainIDs returns the IDs of the primary and additional chains
+func (c *Config) chainIDs() []uint64 {
+	ids := []uint64{c.Chain.ChainID}
+	for _, chain := range c.Chains {
+		ids = append(ids, chain.ChainID)
+	}
+	return ids
+}
+
+// validate checks a chain's endpoint, ID and contract address, prefixing
+// errors with the chain's path
+func (c Chain) validate(prefix string) []error {
+	var errs []error
+	if !validRPCURL(c.RPCURL) {
+		errs = append(errs, fmt.Errorf("%s.rpcUrl: invalid RPC URL %q", prefix, RedactURL(c.RPCURL)))
+	}
+	if c.ChainID == 0 {
+		errs = append(errs, fmt.Errorf("%s.chainId: must be set", prefix))
+	}
+	if c.ContractAddress != "" && !common.IsHexAddress(c.ContractAddress) {
+		errs = append(errs, fmt.Errorf("%s.contractAddress: invalid address %q", prefix, c.ContractAddress))
+	}
+	return errs
+}
+
+// validRPCURL reports whether value is an HTTP, WebSocket or IPC endpoint,
+// or the simulated backend
+func validRPCURL(value string) bool {
+	if value == SimulatedRPCURL {
+		return true
+	}
+	if filepath.IsAbs(value) {
+		return strings.HasSuffix(value, ".ipc")
+	}
+	return validURL(value, "http", "https", "ws", "wss")
+}
+
+// validURL reports whether value is an absolute URL with one of schemes
+func validURL(value string, schemes ...string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,10 @@
 package constants
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Exchange int
 
 const (
@@ -13,3 +18,24 @@ var exchange = map[Exchange]string{
 	GEMINI:  "gemini",
 	KRAKEN:  "kraken",
 }
+
+// Exchanges lists every supported centralized exchange
+var Exchanges = []Exchange{BINANCE, GEMINI, KRAKEN}
+
+// String returns the lower-case exchange identifier
+func (e Exchange) String() string {
+	if name, ok := exchange[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("exchange(%d)", int(e))
+}
+
+// ParseExchange returns the exchange with the given identifier
+func ParseExchange(name string) (Exchange, error) {
+	for e, id := range exchange {
+		if strings.EqualFold(id, name) {
+			return e, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown exchange %q", name)
+}
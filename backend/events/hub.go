@@ -0,0 +1,187 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Topics events are published under
+const (
+	TopicBlocks        = "blocks"
+	TopicConnection    = "connection"
+	TopicOpportunities = "opportunities"
+	TopicTrades        = "trades"
+)
+
+// Topics lists every topic a client can subscribe to
+var Topics = []string{TopicBlocks, TopicConnection, TopicOpportunities, TopicTrades}
+
+// Event types
+const (
+	TypeBlock              = "block"
+	TypeConnectionStatus   = "connection.status"
+	TypeOpportunityAdded   = "opportunity.added"
+	TypeOpportunityExpired = "opportunity.expired"
+	TypeTradeState         = "trade.state"
+
+	// TypeHeartbeat keeps idle connections open and TypeReset tells a
+	// resuming client that events were missed and state should be refetched.
+	// Neither carries an ID.
+	TypeHeartbeat = "heartbeat"
+	TypeReset     = "reset"
+)
+
+// DefaultReplaySize is how many recent events are kept for clients resuming
+// with a Last-Event-ID
+const DefaultReplaySize = 1024
+
+// Event is a typed message published on a topic. IDs increase by one with
+// every event published on the hub.
+type Event struct {
+	ID    uint64
+	Topic string
+	Type  string
+	Data  interface{}
+	Time  time.Time
+}
+
+// Subscription receives the events of the topics it was created with
+type Subscription struct {
+	hub    *Hub
+	topics map[string]bool
+	events chan Event
+	once   sync.Once
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription is cancelled or falls too far behind to keep up, in which
+// case the client should resume from the last event it received.
+func (s *Subscription) Events() <-chan Event { return s.events }
+
+// Close unsubscribes and closes the events channel
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+// wants reports whether the subscription includes topic
+func (s *Subscription) wants(topic string) bool {
+	return len(s.topics) == 0 || s.topics[topic]
+}
+
+// Hub fans events out to subscribers and keeps a bounded backlog for replay
+type Hub struct {
+	mutex       sync.RWMutex
+	nextID      uint64
+	backlog     []Event
+	replaySize  int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewHub creates a hub keeping the last replaySize events
+func NewHub(replaySize int) *Hub {
+	if replaySize <= 0 {
+		replaySize = DefaultReplaySize
+	}
+
+	return &Hub{
+		nextID:      1,
+		replaySize:  replaySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the next ID to an event and sends it to every subscriber
+// of its topic. Subscribers whose buffer is full are dropped rather than
+// holding up the publisher.
+func (h *Hub) Publish(topic, eventType string, data interface{}) Event {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	event := Event{
+		ID:    h.nextID,
+		Topic: topic,
+		Type:  eventType,
+		Data:  data,
+		Time:  time.Now(),
+	}
+	h.nextID++
+
+	h.backlog = append(h.backlog, event)
+	if len(h.backlog) > h.replaySize {
+		h.backlog = h.backlog[len(h.backlog)-h.replaySize:]
+	}
+
+	for sub := range h.subscribers {
+		if !sub.wants(topic) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(h.subscribers, sub)
+			sub.once.Do(func() { close(sub.events) })
+		}
+	}
+
+	return event
+}
+
+// Subscribe registers a subscriber for topics, or every topic when none are
+// given. Events published after lastEventID that are still in the backlog
+// are returned for replay; complete is false when some have already been
+// dropped from it.
+func (h *Hub) Subscribe(topics []string, lastEventID uint64, buffer int) (sub *Subscription, replay []Event, complete bool) {
+	sub = &Subscription{
+		hub:    h,
+		topics: make(map[string]bool),
+		events: make(chan Event, buffer),
+	}
+	for _, topic := range topics {
+		sub.topics[strings.ToLower(topic)] = true
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	complete = true
+	if lastEventID > 0 {
+		// An ID ahead of the hub was issued before a restart
+		if lastEventID >= h.nextID || (len(h.backlog) > 0 && h.backlog[0].ID > lastEventID+1) {
+			complete = false
+		}
+		for _, event := range h.backlog {
+			if event.ID > lastEventID && sub.wants(event.Topic) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	h.subscribers[sub] = struct{}{}
+	return sub, replay, complete
+}
+
+// LastID returns the ID of the most recently published event
+func (h *Hub) LastID() uint64 {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.nextID - 1
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.subscribers, sub)
+	sub.once.Do(func() { close(sub.events) })
+}
+
+// ValidTopic reports whether topic is one clients can subscribe to
+func ValidTopic(topic string) bool {
+	for _, known := range Topics {
+		if strings.EqualFold(known, topic) {
+			return true
+		}
+	}
+	return false
+}
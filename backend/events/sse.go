@@ -0,0 +1,63 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// DefaultHeartbeatInterval is how often an idle stream sends a heartbeat
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// DefaultRetry is the reconnect delay suggested to EventSource clients
+const DefaultRetry = 3 * time.Second
+
+// envelope is the JSON body of every event
+type envelope struct {
+	ID    uint64      `json:"id,omitempty"`
+	Topic string      `json:"topic,omitempty"`
+	Type  string      `json:"type"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data,omitempty"`
+}
+
+// WriteSSE writes event in the Server-Sent Events format. Events without an
+// ID, such as heartbeats, leave the client's Last-Event-ID unchanged.
+func WriteSSE(w io.Writer, event Event) error {
+	data, err := json.Marshal(envelope{
+		ID:    event.ID,
+		Topic: event.Topic,
+		Type:  event.Type,
+		Time:  event.Time,
+		Data:  event.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	if event.ID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
+
+// WriteRetry sets the client's reconnect delay
+func WriteRetry(w io.Writer, retry time.Duration) error {
+	_, err := fmt.Fprintf(w, "retry: %d\n\n", retry.Milliseconds())
+	return err
+}
+
+// ParseLastEventID parses a Last-Event-ID value, returning 0 when it is
+// empty or malformed
+func ParseLastEventID(value string) uint64 {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/blockchain/connection"
+)
+
+const (
+	// DefaultBlockInterval is how often the chain head is polled; Base
+	// produces a block every two seconds
+	DefaultBlockInterval = 2 * time.Second
+
+	// DefaultOpportunityInterval is how often opportunities are rescanned
+	DefaultOpportunityInterval = 15 * time.Second
+)
+
+// FindOpportunities returns the current opportunities keyed by their ID
+type FindOpportunities func(ctx context.Context) (map[string]interface{}, error)
+
+// WatchBlocks publishes a block event for every new chain head until ctx is
+// cancelled. Heads that arrive between polls are coalesced into the latest.
+func WatchBlocks(ctx context.Context, hub *Hub, service *blockchain.BlockchainService, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultBlockInterval
+	}
+
+	var last uint64
+	poll(ctx, interval, func() {
+		headCtx, cancel := context.WithTimeout(ctx, interval)
+		defer cancel()
+
+		header, err := service.GetLatestHeader(headCtx)
+		if err != nil {
+			log.Printf("Warning: Failed to fetch chain head: %v", err)
+			return
+		}
+		number := header.Number.Uint64()
+		if number <= last {
+			return
+		}
+		last = number
+
+		block := map[string]interface{}{
+			"number":    number,
+			"hash":      header.Hash().Hex(),
+			"timestamp": time.Unix(int64(header.Time), 0).Format(time.RFC3339),
+			"gasUsed":   header.GasUsed,
+		}
+		if header.BaseFee != nil {
+			block["baseFeeWei"] = header.BaseFee.String()
+		}
+		hub.Publish(TopicBlocks, TypeBlock, block)
+	})
+}
+
+// WatchConnection publishes the blockchain connection status followed by
+// every transition until ctx is cancelled
+func WatchConnection(ctx context.Context, hub *Hub, service *blockchain.BlockchainService) {
+	transitions, unsubscribe := service.SubscribeConnection(16)
+	defer unsubscribe()
+
+	status := service.GetBlockchainStatus()
+	delete(status, "transitions")
+	status["timestamp"] = time.Now().Format(time.RFC3339)
+	hub.Publish(TopicConnection, TypeConnectionStatus, status)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case transition, ok := <-transitions:
+			if !ok {
+				return
+			}
+			// Carry the same status fields as the initial event
+			event := blockchain.FormatTransition(transition)
+			event["status"] = transition.To.String()
+			event["connected"] = transition.To == connection.StatusConnected
+			hub.Publish(TopicConnection, TypeConnectionStatus, event)
+		}
+	}
+}
+
+// WatchOpportunities rescans opportunities every interval until ctx is
+// cancelled, publishing those that appeared and the IDs of those that
+// expired since the previous scan
+func WatchOpportunities(ctx context.Context, hub *Hub, find FindOpportunities, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultOpportunityInterval
+	}
+
+	live := make(map[string]interface{})
+	poll(ctx, interval, func() {
+		current, err := find(ctx)
+		if err != nil {
+			log.Printf("Warning: Failed to scan opportunities for stream: %v", err)
+			return
+		}
+
+		for id, opportunity := range current {
+			if _, ok := live[id]; !ok {
+				hub.Publish(TopicOpportunities, TypeOpportunityAdded, opportunity)
+			}
+		}
+		for id := range live {
+			if _, ok := current[id]; !ok {
+				hub.Publish(TopicOpportunities, TypeOpportunityExpired, map[string]interface{}{"id": id})
+			}
+		}
+		live = current
+	})
+}
+
+// Trade states
+const (
+	TradeSubmitted = "submitted"
+	TradeConfirmed = "confirmed"
+	TradeFailed    = "failed"
+)
+
+// PublishTrade publishes a change in the state of an executed trade
+func PublishTrade(hub *Hub, txHash, state string, details map[string]interface{}) {
+	trade := map[string]interface{}{
+		"transactionId": txHash,
+		"state":         state,
+	}
+	for key, value := range details {
+		trade[key] = value
+	}
+	hub.Publish(TopicTrades, TypeTradeState, trade)
+}
+
+// poll runs fn immediately and then every interval until ctx is cancelled
+func poll(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,36 +1,51 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 
+	"github.com/arbie-buckets/arbitrage"
 	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/cex"
+	"github.com/arbie-buckets/config"
+	"github.com/arbie-buckets/events"
+	"github.com/arbie-buckets/price"
+	"github.com/arbie-buckets/service/coingecko"
+	"github.com/arbie-buckets/venue"
 )
 
-func init() {
-	// Load environment variables
-	_ = godotenv.Load()
-
-	// Initialize blockchain service with resilient connection
-	if err := blockchain.Initialize(); err != nil {
-		log.Printf("Warning: Failed to initialize blockchain service: %v", err)
-		log.Println("Application will continue and attempt to reconnect automatically")
-	}
-}
-
 func main() {
 	log.Println("Starting Base Network Trading backend...")
 
-	// Load .env files
-	err := godotenv.Load()
+	// Background work runs until shutdown cancels ctx
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Load the config file named by CONFIG_FILE, with environment overrides
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	log.Printf("Effective configuration:\n%s", cfg)
+
+	// Thresholds, tokens, venues and the RPC endpoint can change without a
+	// restart, from the file or POST /api/admin/reload
+	configManager := config.NewManager(os.Getenv("CONFIG_FILE"), cfg)
+
+	// Initialize blockchain service; it keeps retrying in the background
+	// until the chain and contract are reachable
+	if err := blockchain.Initialize(cfg); err != nil {
+		log.Printf("Warning: Failed to initialize blockchain service: %v", err)
 	}
 
 	// Set Gin to production mode
@@ -41,7 +56,7 @@ func main() {
 
 	// Configure CORS
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"},
+		AllowOrigins:     cfg.Server.CORSOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
@@ -49,41 +64,249 @@ func main() {
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// Get blockchain service instance
+	// Trading runs on the primary chain; additional chains serve their
+	// status under /api/chains
 	blockchainService := blockchain.GetService()
 
-	// Log startup status
-	if blockchainService != nil {
-		status := blockchainService.GetBlockchainStatus()
-		if status["connected"].(bool) {
-			log.Println("Blockchain service initialized successfully")
-			log.Printf("Connected to %s (Chain ID: %s)", status["network"], status["chainId"])
+	// Log startup status of every chain
+	for _, service := range blockchain.GetServices() {
+		name := service.Chain().Name
+		state, err := service.State()
+		switch state {
+		case blockchain.StateReady:
+			status := service.GetBlockchainStatus()
+			log.Printf("%s blockchain service initialized successfully", name)
+			log.Printf("Connected to %s (Chain ID: %s)", name, status["chainId"])
 			if addr, ok := status["walletAddress"]; ok {
 				log.Printf("Using wallet address: %s", addr)
 			}
+		case blockchain.StateInitializing:
+			log.Printf("%s blockchain service initializing, connecting in the background", name)
+		default:
+			log.Printf("Warning: %s blockchain service unavailable: %v", name, err)
+		}
+	}
+
+	// Build the venue registry from the enabled venue list. CEX market data
+	// is served from recorded fixtures when a fixtures directory is set.
+	cex.RegisterVenues()
+	venueDeps := venue.Deps{
+		HTTPClient: cex.NewHTTPClient(cfg.Venues.CEXFixturesDir, cfg.Venues.CEXRecordFixtures),
+		Chain:      blockchain.ChainFor(cfg.Chain.ChainID),
+	}
+	if blockchainService != nil {
+		venueDeps.Caller = blockchainService
+	}
+	venues, err := venue.NewRegistryFromConfig(venue.ParseEnabled(strings.Join(cfg.Venues.Enabled, ",")), venueDeps)
+	if err != nil {
+		log.Fatalf("Failed to configure venues: %v", err)
+	}
+	for _, v := range venues.List() {
+		log.Printf("Venue enabled: %s (%s)", v.Name(), v.Kind())
+	}
+
+	// Stream CEX order books over WebSockets when enabled
+	feed := cex.NewFeed()
+	if cfg.Venues.CEXStreaming {
+		streamConfig := cex.DefaultStreamConfig()
+		if cfg.Venues.CEXMaxBookAge > 0 {
+			streamConfig.MaxBookAge = time.Duration(cfg.Venues.CEXMaxBookAge)
+		}
+		feed, err = cex.NewFeedFromVenues(venues.ByKind(venue.KindCEX), streamConfig)
+		if err != nil {
+			log.Fatalf("Failed to configure CEX streams: %v", err)
+		}
+		for _, stream := range feed.Streams() {
+			log.Printf("Streaming %s order books", stream.Exchange())
+		}
+		go feed.Run(ctx)
+	}
+
+	// Search routes between the scanned tokens across every registered venue
+	tokens, err := blockchain.FindTokens(cfg.Trading.Tokens)
+	if err != nil {
+		log.Fatalf("Failed to configure tokens: %v", err)
+	}
+	routeFinder := arbitrage.NewRouteFinder(venues, tokens, routeConfig(cfg))
+
+	// Reference prices come from an oracle taking the median of Chainlink
+	// feeds, CoinGecko (when a key is configured), the CEX tickers and a TWAP
+	// of DEX quotes
+	usdc, _ := blockchain.FindToken("USDC")
+	eth, _ := blockchain.FindToken("ETH")
+	var priceSources []price.PriceSource
+	if blockchainService != nil {
+		chain := blockchainService.Chain()
+		feeds := blockchain.NewChainlinkRegistry(chain.ChainlinkFeeds...)
+		overrides, err := blockchain.ParseChainlinkFeeds(strings.Join(cfg.Prices.ChainlinkFeeds, ","))
+		if err != nil {
+			log.Fatalf("Failed to configure Chainlink feeds: %v", err)
+		}
+		for _, feed := range overrides {
+			feeds.Register(feed)
+		}
+
+		if len(feeds.Feeds()) == 0 {
+			log.Printf("No Chainlink feeds known on %s; configure prices.chainlinkFeeds to use them", chain.Name)
 		} else {
-			log.Println("Warning: Blockchain service not connected, will attempt reconnection automatically")
+			reader, err := blockchain.NewChainlinkReader(blockchainService, feeds, blockchain.DefaultChainlinkGrace)
+			if err != nil {
+				log.Fatalf("Failed to create Chainlink reader: %v", err)
+			}
+			priceSources = append(priceSources, price.NewChainlinkSource(reader))
+		}
+	}
+	if apiKey := string(cfg.Prices.CoinGeckoAPIKey); apiKey != "" {
+		priceSources = append(priceSources, coingecko.NewClient(coingecko.DefaultConfig(apiKey)))
+	}
+	for _, v := range venues.ByKind(venue.KindCEX) {
+		if cexVenue, ok := v.(*cex.Venue); ok {
+			priceSources = append(priceSources, cex.NewTickerSource(cexVenue.Connector()))
+		}
+	}
+	dexTWAP := price.NewTWAPSource(price.NewVenueSource(venues, usdc), blockchain.DefaultTokens, price.DefaultTWAPWindow, price.DefaultTWAPInterval)
+	go dexTWAP.Run(ctx)
+	priceSources = append(priceSources, dexTWAP)
+
+	oracleConfig := price.DefaultOracleConfig()
+	if cfg.Prices.MaxDeviation > 0 {
+		oracleConfig.MaxDeviation = cfg.Prices.MaxDeviation
+	}
+	if cfg.Prices.MaxAge > 0 {
+		oracleConfig.MaxAge = time.Duration(cfg.Prices.MaxAge)
+	}
+	prices := price.NewOracle(oracleConfig, priceSources...)
+	log.Printf("Price oracle sources: %s", strings.Join(prices.Sources(), ", "))
+
+	// Keep 1m, 1h and 1d candles of oracle prices for charts and 24h change
+	historyConfig := price.DefaultHistoryConfig()
+	historyConfig.Path = cfg.Prices.HistoryFile
+	if cfg.Prices.SampleInterval > 0 {
+		historyConfig.SampleInterval = time.Duration(cfg.Prices.SampleInterval)
+	}
+	history, err := price.NewHistory(prices, blockchain.DefaultTokens, historyConfig)
+	if err != nil {
+		log.Fatalf("Failed to load price history: %v", err)
+	}
+	go history.Run(ctx)
+
+	// Size each route against the wallet, per-trade and pool share limits
+	sizer := arbitrage.NewSizer(blockchainService, prices, sizingLimits(cfg))
+	scanner := arbitrage.NewScanner(routeFinder, sizer)
+
+	// Net profit accounts for L2 gas and the L1 data fee
+	profitCalculator := arbitrage.NewProfitCalculator(blockchainService, prices, eth)
+
+	// Executions derive minReturn from a fresh quote and a slippage tolerance
+	slippageGuard := arbitrage.NewSlippageGuard(venues, blockchainService, slippageConfig(cfg))
+
+	// Compare DEX quotes against CEX books when the cex-dex mode is enabled,
+	// consuming streamed book updates when available and REST snapshots
+	// otherwise
+	var spreadScanner *arbitrage.SpreadScanner
+	if cfg.Venues.CEXDEXMode {
+		var books cex.BookSource = cex.NewRESTBooks(venues.ByKind(venue.KindCEX))
+		if len(feed.Streams()) > 0 {
+			books = feed
+		}
+		spreadScanner = arbitrage.NewSpreadScanner(venues, books, tokens, prices, profitCalculator, spreadConfig(cfg))
+		if len(feed.Streams()) > 0 {
+			updates, unsubscribe := feed.SubscribeBooks(0, 64)
+			defer unsubscribe()
+			go spreadScanner.Watch(ctx, updates)
 		}
-	} else {
-		log.Println("Warning: Blockchain service not available, running in limited mode")
-		log.Println("Application will attempt to reconnect automatically")
+	}
+
+	// A reload applies to the scanners, the venue registry and the
+	// connection pool together, or to none of them
+	configManager.OnReload("scanner", reconfigureScanner(routeFinder, sizer, slippageGuard, spreadScanner))
+	configManager.OnReload("venues", reconfigureVenues(venues, venueDeps))
+	for _, service := range blockchain.GetServices() {
+		configManager.OnReload("blockchain "+service.Chain().Name, service.ApplyConfig)
+	}
+	go configManager.Watch(ctx, config.DefaultWatchInterval)
+
+	// Push new heads, connection changes, opportunities and trade states to
+	// clients of the event stream
+	hub := events.NewHub(events.DefaultReplaySize)
+	if blockchainService != nil {
+		go events.WatchBlocks(ctx, hub, blockchainService, events.DefaultBlockInterval)
+		go events.WatchConnection(ctx, hub, blockchainService)
+
+		opportunityInterval := events.DefaultOpportunityInterval
+		if cfg.Stream.OpportunityInterval > 0 {
+			opportunityInterval = time.Duration(cfg.Stream.OpportunityInterval)
+		}
+		go events.WatchOpportunities(ctx, hub, func(ctx context.Context) (map[string]interface{}, error) {
+			opportunities, err := findOpportunities(ctx, blockchainService, scanner, spreadScanner, profitCalculator)
+			if err != nil {
+				return nil, err
+			}
+			live := make(map[string]interface{}, len(opportunities))
+			for i, opp := range opportunities {
+				formatted := formatOpportunity(i, opp)
+				live[formatted["id"].(string)] = formatted
+			}
+			return live, nil
+		}, opportunityInterval)
+	}
+
+	// Follow executed trades until they are mined, resuming those left
+	// pending by the previous run
+	tracker, err := arbitrage.NewTradeTracker(blockchainService, hub, cfg.Trading.PendingTradesFile)
+	if err != nil {
+		log.Fatalf("Failed to load pending trades: %v", err)
 	}
 
 	// Set up API routes with the blockchain service
-	SetupRoutes(r, blockchainService)
+	SetupRoutes(r, blockchainService, venues, feed, prices, history, scanner, spreadScanner, profitCalculator, slippageGuard, tracker, hub, configManager)
+
+	port := cfg.Server.Port
 
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Start server. Request contexts derive from ctx so that open event
+	// streams end on shutdown.
+	server := &http.Server{
+		Addr:        ":" + port,
+		Handler:     r,
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		serverErr <- server.ListenAndServe()
+	}()
 
-	// Start server
-	log.Printf("Server starting on port %s", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
+	// Run until SIGINT or SIGTERM
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-serverErr:
 		log.Fatalf("Failed to start server: %v", err)
+	case <-signals.Done():
+		stop()
+	}
+
+	shutdownTimeout := time.Duration(configManager.Current().Server.ShutdownTimeout)
+	log.Printf("Shutting down, waiting up to %s for trades in flight", shutdownTimeout)
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	// Refuse new executes and drain sends and receipt tracking first, while
+	// the connection is still up
+	if err := tracker.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Warning: Trades still pending at shutdown: %v", err)
+	}
+	if pending := tracker.Pending(); len(pending) > 0 {
+		log.Printf("Saved %d pending trades", len(pending))
+	}
+
+	// Stop background work and event streams, then the server
+	cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Warning: Server shutdown incomplete: %v", err)
 	}
 
-	// Close blockchain connection when server stops
-	defer blockchain.Close()
+	// Close blockchain connection last
+	blockchain.Close()
+	log.Println("Shutdown complete")
 }
@@ -0,0 +1,44 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+// ChainlinkSource prices tokens from their Chainlink USD feeds
+type ChainlinkSource struct {
+	reader *blockchain.ChainlinkReader
+}
+
+// NewChainlinkSource creates a price source over reader
+func NewChainlinkSource(reader *blockchain.ChainlinkReader) *ChainlinkSource {
+	return &ChainlinkSource{reader: reader}
+}
+
+// Name returns the source name
+func (s *ChainlinkSource) Name() string { return "chainlink" }
+
+// Price returns the answer of the token's latest validated round. Feeds
+// update only on deviation or at their heartbeat, which the reader already
+// enforces, so the price is timestamped when it was read rather than when
+// the round was last updated.
+func (s *ChainlinkSource) Price(ctx context.Context, token blockchain.TokenInfo) (*Price, error) {
+	round, err := s.reader.LatestRound(ctx, token.Symbol)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrNoFeed) {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedToken, err)
+		}
+		return nil, err
+	}
+
+	return &Price{
+		Token:     token,
+		USD:       round.Price(),
+		Source:    s.Name(),
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -0,0 +1,319 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+// Interval is a candle width
+type Interval string
+
+// Supported candle intervals
+const (
+	Interval1m Interval = "1m"
+	Interval1h Interval = "1h"
+	Interval1d Interval = "1d"
+)
+
+// Intervals lists the supported intervals from finest to coarsest
+var Intervals = []Interval{Interval1m, Interval1h, Interval1d}
+
+// Duration returns the width of the interval
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Interval1m:
+		return time.Minute
+	case Interval1h:
+		return time.Hour
+	case Interval1d:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// ParseInterval parses 1m, 1h or 1d
+func ParseInterval(value string) (Interval, error) {
+	for _, interval := range Intervals {
+		if string(interval) == strings.ToLower(value) {
+			return interval, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported interval %q, expected one of 1m, 1h, 1d", value)
+}
+
+// DefaultSampleInterval is how often prices are recorded
+const DefaultSampleInterval = 30 * time.Second
+
+// DefaultRetention is how long candles of each interval are kept
+var DefaultRetention = map[Interval]time.Duration{
+	Interval1m: 24 * time.Hour,
+	Interval1h: 30 * 24 * time.Hour,
+	Interval1d: 365 * 24 * time.Hour,
+}
+
+// Candle is the open, high, low and close price over one interval
+type Candle struct {
+	Start   time.Time `json:"start"`
+	Open    float64   `json:"open"`
+	High    float64   `json:"high"`
+	Low     float64   `json:"low"`
+	Close   float64   `json:"close"`
+	Samples int       `json:"samples"`
+}
+
+// HistoryConfig configures a price history store
+type HistoryConfig struct {
+	SampleInterval time.Duration
+	Retention      map[Interval]time.Duration
+
+	// Path persists candles across restarts when set
+	Path string
+}
+
+// DefaultHistoryConfig returns the default sampling and retention
+func DefaultHistoryConfig() HistoryConfig {
+	return HistoryConfig{
+		SampleInterval: DefaultSampleInterval,
+		Retention:      DefaultRetention,
+	}
+}
+
+// History samples token prices and keeps 1m, 1h and 1d candles of them
+type History struct {
+	source PriceSource
+	tokens []blockchain.TokenInfo
+	config HistoryConfig
+
+	mutex   sync.RWMutex
+	candles map[string]map[Interval][]Candle
+}
+
+// NewHistory creates a store sampling tokens from source, filling unset
+// config fields from DefaultHistoryConfig. Candles saved at config.Path are
+// loaded.
+func NewHistory(source PriceSource, tokens []blockchain.TokenInfo, config HistoryConfig) (*History, error) {
+	if config.SampleInterval <= 0 {
+		config.SampleInterval = DefaultSampleInterval
+	}
+	// Intervals missing from a configured retention keep their default
+	retention := make(map[Interval]time.Duration, len(DefaultRetention))
+	for interval, keep := range DefaultRetention {
+		retention[interval] = keep
+	}
+	for interval, keep := range config.Retention {
+		retention[interval] = keep
+	}
+	config.Retention = retention
+
+	h := &History{
+		source:  source,
+		tokens:  tokens,
+		config:  config,
+		candles: make(map[string]map[Interval][]Candle),
+	}
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Run samples every token each interval until ctx is cancelled
+func (h *History) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.config.SampleInterval)
+	defer ticker.Stop()
+
+	for {
+		h.sampleAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Record adds a price observed at a point in time to every interval
+func (h *History) Record(token blockchain.TokenInfo, usd float64, at time.Time) {
+	key := strings.ToLower(token.Address)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	series, ok := h.candles[key]
+	if !ok {
+		series = make(map[Interval][]Candle)
+		h.candles[key] = series
+	}
+
+	for _, interval := range Intervals {
+		start := at.UTC().Truncate(interval.Duration())
+		candles := series[interval]
+
+		if n := len(candles); n > 0 && candles[n-1].Start.Equal(start) {
+			last := &candles[n-1]
+			last.High = max(last.High, usd)
+			last.Low = min(last.Low, usd)
+			last.Close = usd
+			last.Samples++
+		} else {
+			candles = append(candles, Candle{Start: start, Open: usd, High: usd, Low: usd, Close: usd, Samples: 1})
+		}
+
+		series[interval] = prune(candles, at.Add(-h.config.Retention[interval]))
+	}
+}
+
+// Candles returns the token's candles of an interval starting at or after
+// since, oldest first
+func (h *History) Candles(token blockchain.TokenInfo, interval Interval, since time.Time) []Candle {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	candles := h.candles[strings.ToLower(token.Address)][interval]
+	result := []Candle{}
+	for _, candle := range candles {
+		if !candle.Start.Before(since) {
+			result = append(result, candle)
+		}
+	}
+	return result
+}
+
+// Change returns the percentage change between the latest price and the
+// price window ago. ok is false if the history does not reach that far, if
+// it has no current price, or if it has a gap at the start of the window,
+// as left by a restart.
+func (h *History) Change(token blockchain.TokenInfo, window time.Duration) (float64, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	series := h.candles[strings.ToLower(token.Address)]
+	now := time.Now()
+	cutoff := now.Add(-window)
+
+	// The latest price must be from the current sampling, not the last one
+	// before a gap
+	finest := series[Intervals[0]]
+	if len(finest) == 0 || now.Sub(finest[len(finest)-1].Start) > Intervals[0].Duration()+h.config.SampleInterval {
+		return 0, false
+	}
+	latest := finest[len(finest)-1].Close
+
+	// Use the finest interval whose retention still covers the window. The
+	// past price is the close of the last candle to end by the cutoff.
+	for _, interval := range Intervals {
+		width := interval.Duration()
+		var past *Candle
+		for i, candle := range series[interval] {
+			if candle.Start.Add(width).After(cutoff) {
+				break
+			}
+			past = &series[interval][i]
+		}
+
+		// Across a gap that candle can end long before the cutoff; a
+		// coarser interval may still cover it
+		if past == nil || cutoff.Sub(past.Start.Add(width)) >= width {
+			continue
+		}
+		if past.Close <= 0 {
+			return 0, false
+		}
+		return (latest - past.Close) / past.Close * 100, true
+	}
+	return 0, false
+}
+
+// sampleAll records the current price of every token and persists the
+// candles when a path is configured
+func (h *History) sampleAll(ctx context.Context) {
+	for _, token := range h.tokens {
+		sampleCtx, cancel := context.WithTimeout(ctx, h.config.SampleInterval)
+		p, err := h.source.Price(sampleCtx, token)
+		cancel()
+		if err != nil {
+			if !errors.Is(err, ErrUnsupportedToken) {
+				log.Printf("Warning: Failed to sample %s price for history: %v", token.Symbol, err)
+			}
+			continue
+		}
+		h.Record(token, p.USD, time.Now())
+	}
+
+	if err := h.save(); err != nil {
+		log.Printf("Warning: Failed to save price history: %v", err)
+	}
+}
+
+// load reads persisted candles, if any, dropping those that fell out of
+// retention while the history was not running
+func (h *History) load() error {
+	if h.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(h.config.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read price history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &h.candles); err != nil {
+		return fmt.Errorf("failed to decode price history: %w", err)
+	}
+
+	now := time.Now()
+	for _, series := range h.candles {
+		for interval, candles := range series {
+			series[interval] = prune(candles, now.Add(-h.config.Retention[interval]))
+		}
+	}
+	return nil
+}
+
+// save writes the candles to the configured path, replacing the file
+// atomically
+func (h *History) save() error {
+	if h.config.Path == "" {
+		return nil
+	}
+
+	h.mutex.RLock()
+	data, err := json.Marshal(h.candles)
+	h.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.config.Path), 0o755); err != nil {
+		return err
+	}
+	tmp := h.config.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.config.Path)
+}
+
+// prune drops candles that started before cutoff
+func prune(candles []Candle, cutoff time.Time) []Candle {
+	i := 0
+	for i < len(candles) && candles[i].Start.Before(cutoff) {
+		i++
+	}
+	return candles[i:]
+}
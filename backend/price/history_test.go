@@ -0,0 +1,114 @@
+package price
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+func newTestHistory(t *testing.T, path string) *History {
+	t.Helper()
+	history, err := NewHistory(nil, nil, HistoryConfig{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return history
+}
+
+func TestHistoryChange(t *testing.T) {
+	eth, _ := blockchain.FindToken("ETH")
+	history := newTestHistory(t, "")
+
+	// A sample every minute for two hours, the price rising from 100 to 150
+	// an hour ago
+	now := time.Now()
+	for i := 120; i >= 0; i-- {
+		usd := 150.0
+		if i >= 60 {
+			usd = 100
+		}
+		history.Record(eth, usd, now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	change, ok := history.Change(eth, time.Hour)
+	if !ok || math.Abs(change-50) > 1e-9 {
+		t.Errorf("Change(1h) = %v, %v, want 50, true", change, ok)
+	}
+	if _, ok := history.Change(eth, 3*time.Hour); ok {
+		t.Error("Change(3h) succeeded beyond the recorded history")
+	}
+}
+
+func TestHistoryChangeAcrossGap(t *testing.T) {
+	eth, _ := blockchain.FindToken("ETH")
+	now := time.Now()
+
+	// Sampling stopped ten days ago and resumed now
+	resumed := newTestHistory(t, "")
+	resumed.Record(eth, 100, now.Add(-10*24*time.Hour))
+	resumed.Record(eth, 150, now)
+	if change, ok := resumed.Change(eth, time.Hour); ok {
+		t.Errorf("Change(1h) = %v across a ten day gap, want no change", change)
+	}
+
+	// Sampling stopped ten minutes ago
+	stopped := newTestHistory(t, "")
+	for i := 120; i >= 10; i-- {
+		stopped.Record(eth, 100, now.Add(-time.Duration(i)*time.Minute))
+	}
+	if change, ok := stopped.Change(eth, time.Hour); ok {
+		t.Errorf("Change(1h) = %v without a current price, want no change", change)
+	}
+}
+
+func TestHistoryLoadAppliesRetention(t *testing.T) {
+	eth, _ := blockchain.FindToken("ETH")
+	now := time.Now().UTC()
+	recent := now.Truncate(time.Minute)
+	saved := map[string]map[Interval][]Candle{
+		strings.ToLower(eth.Address): {
+			Interval1m: {
+				{Start: recent.Add(-48 * time.Hour), Open: 90, High: 90, Low: 90, Close: 90, Samples: 1},
+				{Start: recent, Open: 100, High: 100, Low: 100, Close: 100, Samples: 1},
+			},
+		},
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	candles := newTestHistory(t, path).Candles(eth, Interval1m, time.Time{})
+	if len(candles) != 1 || !candles[0].Start.Equal(recent) {
+		t.Errorf("loaded %v, want only the candle within the 1m retention", candles)
+	}
+}
+
+func TestHistoryRetentionDefaultsMissingIntervals(t *testing.T) {
+	eth, _ := blockchain.FindToken("ETH")
+	history, err := NewHistory(nil, nil, HistoryConfig{Retention: map[Interval]time.Duration{Interval1m: time.Hour}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	history.Record(eth, 100, now.Add(-3*time.Hour))
+	history.Record(eth, 110, now)
+
+	if candles := history.Candles(eth, Interval1m, time.Time{}); len(candles) != 1 {
+		t.Errorf("kept %d 1m candles, want the one within the configured hour", len(candles))
+	}
+	if candles := history.Candles(eth, Interval1h, time.Time{}); len(candles) != 2 {
+		t.Errorf("kept %d 1h candles, want both within the default retention", len(candles))
+	}
+}
@@ -0,0 +1,250 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+const (
+	// DefaultMaxAge drops source prices older than this
+	DefaultMaxAge = 5 * time.Minute
+
+	// DefaultMaxDeviation drops source prices further than this fraction
+	// from the median of all fresh prices
+	DefaultMaxDeviation = 0.02
+
+	// DefaultMinSources is the number of agreeing sources required
+	DefaultMinSources = 1
+
+	// DefaultSourceTimeout bounds each source's answer
+	DefaultSourceTimeout = 5 * time.Second
+)
+
+// Reasons a source price was left out of an aggregate
+const (
+	RejectedStale   = "stale"
+	RejectedOutlier = "outlier"
+	RejectedError   = "error"
+)
+
+// ErrInsufficientSources is returned when too few sources agree on a price
+var ErrInsufficientSources = errors.New("not enough agreeing price sources")
+
+// OracleConfig controls which source prices an Oracle accepts
+type OracleConfig struct {
+	MaxAge        time.Duration
+	MaxDeviation  float64
+	MinSources    int
+	SourceTimeout time.Duration
+}
+
+// DefaultOracleConfig returns the default staleness and deviation limits
+func DefaultOracleConfig() OracleConfig {
+	return OracleConfig{
+		MaxAge:        DefaultMaxAge,
+		MaxDeviation:  DefaultMaxDeviation,
+		MinSources:    DefaultMinSources,
+		SourceTimeout: DefaultSourceTimeout,
+	}
+}
+
+// SourcePrice is one source's answer within an aggregate
+type SourcePrice struct {
+	Source    string
+	USD       float64
+	Timestamp time.Time
+	Used      bool
+
+	// Rejected is RejectedStale, RejectedOutlier or RejectedError when the
+	// price was not used, and Error holds the source's error
+	Rejected string
+	Error    string
+}
+
+// AggregatePrice is the oracle's answer for a token
+type AggregatePrice struct {
+	Token blockchain.TokenInfo
+	USD   float64
+
+	// Confidence is between 0 and 1: the share of answering sources that
+	// were used, reduced as their spread approaches the allowed deviation
+	Confidence float64
+
+	// Deviation is the largest relative distance of a used price from USD
+	Deviation float64
+
+	Sources []SourcePrice
+
+	// Timestamp is the time of the oldest price used
+	Timestamp time.Time
+}
+
+// Oracle combines several price sources into a median price, dropping
+// sources that are stale or deviate too far from the others
+type Oracle struct {
+	sources []PriceSource
+	config  OracleConfig
+}
+
+// NewOracle creates an oracle over sources, filling unset config fields
+// from DefaultOracleConfig
+func NewOracle(config OracleConfig, sources ...PriceSource) *Oracle {
+	defaults := DefaultOracleConfig()
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaults.MaxAge
+	}
+	if config.MaxDeviation <= 0 {
+		config.MaxDeviation = defaults.MaxDeviation
+	}
+	if config.MinSources <= 0 {
+		config.MinSources = defaults.MinSources
+	}
+	if config.SourceTimeout <= 0 {
+		config.SourceTimeout = defaults.SourceTimeout
+	}
+
+	return &Oracle{
+		sources: sources,
+		config:  config,
+	}
+}
+
+// Name returns the source name
+func (o *Oracle) Name() string { return "oracle" }
+
+// Sources returns the names of the configured sources
+func (o *Oracle) Sources() []string {
+	names := make([]string, len(o.sources))
+	for i, source := range o.sources {
+		names[i] = source.Name()
+	}
+	return names
+}
+
+// Price implements PriceSource with the aggregated price
+func (o *Oracle) Price(ctx context.Context, token blockchain.TokenInfo) (*Price, error) {
+	aggregate, err := o.Aggregate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Price{
+		Token:     token,
+		USD:       aggregate.USD,
+		Source:    o.Name(),
+		Timestamp: aggregate.Timestamp,
+	}, nil
+}
+
+// Aggregate asks every source concurrently and returns the median of the
+// fresh prices that agree within the configured deviation
+func (o *Oracle) Aggregate(ctx context.Context, token blockchain.TokenInfo) (*AggregatePrice, error) {
+	answers := o.collect(ctx, token)
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("no source can price %s: %w", token.Symbol, ErrUnsupportedToken)
+	}
+
+	// Drop failed and stale answers
+	now := time.Now()
+	var fresh []float64
+	for i := range answers {
+		switch {
+		case answers[i].Error != "":
+			answers[i].Rejected = RejectedError
+		case now.Sub(answers[i].Timestamp) > o.config.MaxAge:
+			answers[i].Rejected = RejectedStale
+		default:
+			fresh = append(fresh, answers[i].USD)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil, fmt.Errorf("%w: no fresh price for %s", ErrInsufficientSources, token.Symbol)
+	}
+
+	// Drop outliers around the median of the fresh answers, then take the
+	// median of what remains
+	center := median(fresh)
+	var used []float64
+	for i := range answers {
+		if answers[i].Rejected != "" {
+			continue
+		}
+		if math.Abs(answers[i].USD-center)/center > o.config.MaxDeviation {
+			answers[i].Rejected = RejectedOutlier
+			continue
+		}
+		answers[i].Used = true
+		used = append(used, answers[i].USD)
+	}
+	if len(used) < o.config.MinSources {
+		return nil, fmt.Errorf("%w: %d of %d required sources agree on %s",
+			ErrInsufficientSources, len(used), o.config.MinSources, token.Symbol)
+	}
+
+	aggregate := &AggregatePrice{
+		Token:   token,
+		USD:     median(used),
+		Sources: answers,
+	}
+	for _, answer := range answers {
+		if !answer.Used {
+			continue
+		}
+		aggregate.Deviation = math.Max(aggregate.Deviation, math.Abs(answer.USD-aggregate.USD)/aggregate.USD)
+		if aggregate.Timestamp.IsZero() || answer.Timestamp.Before(aggregate.Timestamp) {
+			aggregate.Timestamp = answer.Timestamp
+		}
+	}
+
+	coverage := float64(len(used)) / float64(len(answers))
+	agreement := math.Max(0, 1-aggregate.Deviation/o.config.MaxDeviation)
+	aggregate.Confidence = coverage * agreement
+
+	return aggregate, nil
+}
+
+// collect asks every source for a price, leaving out sources that do not
+// support the token
+func (o *Oracle) collect(ctx context.Context, token blockchain.TokenInfo) []SourcePrice {
+	ctx, cancel := context.WithTimeout(ctx, o.config.SourceTimeout)
+	defer cancel()
+
+	answers := make([]*SourcePrice, len(o.sources))
+	var wg sync.WaitGroup
+	for i, source := range o.sources {
+		wg.Add(1)
+		go func(i int, source PriceSource) {
+			defer wg.Done()
+
+			p, err := source.Price(ctx, token)
+			if errors.Is(err, ErrUnsupportedToken) {
+				return
+			}
+
+			answer := &SourcePrice{Source: source.Name()}
+			if err != nil {
+				answer.Error = err.Error()
+			} else {
+				answer.USD = p.USD
+				answer.Timestamp = p.Timestamp
+			}
+			answers[i] = answer
+		}(i, source)
+	}
+	wg.Wait()
+
+	// Keep the configured source order
+	var collected []SourcePrice
+	for _, answer := range answers {
+		if answer != nil {
+			collected = append(collected, *answer)
+		}
+	}
+	return collected
+}
@@ -0,0 +1,29 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+// ErrUnsupportedToken is returned when a source cannot price a token
+var ErrUnsupportedToken = errors.New("token not supported by price source")
+
+// Price is a USD price for a token as reported by a single source
+type Price struct {
+	Token     blockchain.TokenInfo
+	USD       float64
+	Source    string
+	Timestamp time.Time
+}
+
+// PriceSource reports USD prices for tokens
+type PriceSource interface {
+	// Name identifies the source in logs and API responses
+	Name() string
+
+	// Price returns the current USD price of one whole token
+	Price(ctx context.Context, token blockchain.TokenInfo) (*Price, error)
+}
@@ -0,0 +1,146 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+const (
+	// DefaultTWAPWindow is the period prices are averaged over
+	DefaultTWAPWindow = 10 * time.Minute
+
+	// DefaultTWAPInterval is how often the underlying source is sampled
+	DefaultTWAPInterval = 30 * time.Second
+)
+
+// sample is a price observed at a point in time
+type sample struct {
+	usd float64
+	at  time.Time
+}
+
+// TWAPSource samples another source and reports the time-weighted average
+// price over a trailing window. Used over DEX quotes it smooths out single
+// block manipulation of pool prices.
+type TWAPSource struct {
+	source   PriceSource
+	tokens   []blockchain.TokenInfo
+	window   time.Duration
+	interval time.Duration
+
+	mutex   sync.RWMutex
+	samples map[string][]sample
+}
+
+// NewTWAPSource creates a TWAP over source for tokens. Run must be started
+// for samples to be collected.
+func NewTWAPSource(source PriceSource, tokens []blockchain.TokenInfo, window, interval time.Duration) *TWAPSource {
+	if window <= 0 {
+		window = DefaultTWAPWindow
+	}
+	if interval <= 0 {
+		interval = DefaultTWAPInterval
+	}
+
+	return &TWAPSource{
+		source:   source,
+		tokens:   tokens,
+		window:   window,
+		interval: interval,
+		samples:  make(map[string][]sample),
+	}
+}
+
+// Name returns the underlying source's name with a -twap suffix
+func (s *TWAPSource) Name() string { return s.source.Name() + "-twap" }
+
+// Run samples every token each interval until ctx is cancelled
+func (s *TWAPSource) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, token := range s.tokens {
+			s.sample(ctx, token)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Price returns the time-weighted average over the window. Until two
+// samples have been collected the underlying source is asked directly.
+func (s *TWAPSource) Price(ctx context.Context, token blockchain.TokenInfo) (*Price, error) {
+	s.mutex.RLock()
+	samples := s.samples[strings.ToLower(token.Address)]
+	s.mutex.RUnlock()
+
+	if len(samples) < 2 {
+		p, err := s.source.Price(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		return &Price{Token: token, USD: p.USD, Source: s.Name(), Timestamp: p.Timestamp}, nil
+	}
+
+	// Each sample holds until the next one; the last holds until now
+	now := time.Now()
+	var weighted, total float64
+	for i, current := range samples {
+		end := now
+		if i+1 < len(samples) {
+			end = samples[i+1].at
+		}
+		weight := end.Sub(current.at).Seconds()
+		weighted += current.usd * weight
+		total += weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("empty TWAP window for %s", token.Symbol)
+	}
+
+	return &Price{
+		Token:     token,
+		USD:       weighted / total,
+		Source:    s.Name(),
+		Timestamp: samples[len(samples)-1].at,
+	}, nil
+}
+
+// sample records the current price of token and drops samples that have
+// left the window
+func (s *TWAPSource) sample(ctx context.Context, token blockchain.TokenInfo) {
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	p, err := s.source.Price(ctx, token)
+	if err != nil {
+		if !errors.Is(err, ErrUnsupportedToken) {
+			log.Printf("Warning: Failed to sample %s price for TWAP: %v", token.Symbol, err)
+		}
+		return
+	}
+
+	key := strings.ToLower(token.Address)
+	cutoff := time.Now().Add(-s.window)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	samples := append(s.samples[key], sample{usd: p.USD, at: time.Now()})
+	for len(samples) > 0 && samples[0].at.Before(cutoff) {
+		samples = samples[1:]
+	}
+	s.samples[key] = samples
+}
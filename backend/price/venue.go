@@ -0,0 +1,80 @@
+package price
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/venue"
+)
+
+// VenueSource prices tokens by quoting one whole token into a USD
+// stablecoin on every registered DEX venue and taking the median. CEX
+// venues are priced separately from their tickers.
+type VenueSource struct {
+	registry *venue.Registry
+	usd      blockchain.TokenInfo
+}
+
+// NewVenueSource creates a price source that quotes against usd, which is
+// assumed to trade at one dollar
+func NewVenueSource(registry *venue.Registry, usd blockchain.TokenInfo) *VenueSource {
+	return &VenueSource{
+		registry: registry,
+		usd:      usd,
+	}
+}
+
+// Name returns the source name
+func (s *VenueSource) Name() string { return "dex" }
+
+// Price returns the median USD price of one whole token across venues
+func (s *VenueSource) Price(ctx context.Context, token blockchain.TokenInfo) (*Price, error) {
+	if strings.EqualFold(token.Address, s.usd.Address) {
+		return &Price{Token: token, USD: 1, Source: s.Name(), Timestamp: time.Now()}, nil
+	}
+
+	pair := venue.Pair{Base: token, Quote: s.usd}
+	amountIn := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil)
+
+	var prices []float64
+	for _, v := range s.registry.SupportingPair(pair) {
+		if v.Kind() != venue.KindDEX {
+			continue
+		}
+		quote, err := v.Quote(ctx, pair, amountIn)
+		if err != nil || quote.AmountOut.Sign() <= 0 {
+			continue
+		}
+		out, _ := new(big.Float).SetInt(quote.AmountOut).Float64()
+		prices = append(prices, out/math.Pow10(int(s.usd.Decimals)))
+	}
+
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("no venue could price %s: %w", token.Symbol, ErrUnsupportedToken)
+	}
+
+	return &Price{
+		Token:     token,
+		USD:       median(prices),
+		Source:    s.Name(),
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// median returns the middle value of a non-empty slice
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
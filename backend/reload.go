@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/arbie-buckets/arbitrage"
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/config"
+	"github.com/arbie-buckets/venue"
+)
+
+// routeConfig returns the route search settings of cfg
+func routeConfig(cfg *config.Config) arbitrage.Config {
+	routeConfig := arbitrage.DefaultConfig()
+	if cfg.Trading.RouteMaxHops > 0 {
+		routeConfig.MaxHops = cfg.Trading.RouteMaxHops
+	}
+	return routeConfig
+}
+
+// sizingLimits returns the wallet, per-trade and pool share limits of cfg
+func sizingLimits(cfg *config.Config) arbitrage.SizingLimits {
+	limits := arbitrage.DefaultSizingLimits()
+	if cfg.Trading.MaxTradeUSD > 0 {
+		limits.MaxTradeUSD = cfg.Trading.MaxTradeUSD
+	}
+	if cfg.Trading.MaxPoolShare > 0 {
+		limits.MaxPoolShare = cfg.Trading.MaxPoolShare
+	}
+	return limits
+}
+
+// slippageConfig returns the execution tolerance and quote age of cfg
+func slippageConfig(cfg *config.Config) arbitrage.SlippageConfig {
+	slippageConfig := arbitrage.DefaultSlippageConfig()
+	if cfg.Trading.SlippageToleranceBps > 0 {
+		slippageConfig.ToleranceBps = cfg.Trading.SlippageToleranceBps
+	}
+	if cfg.Trading.MaxQuoteAgeBlocks > 0 {
+		slippageConfig.MaxQuoteAgeBlocks = cfg.Trading.MaxQuoteAgeBlocks
+	}
+	return slippageConfig
+}
+
+// spreadConfig returns the CEX–DEX thresholds of cfg
+func spreadConfig(cfg *config.Config) arbitrage.SpreadConfig {
+	spreadConfig := arbitrage.DefaultSpreadConfig()
+	spreadConfig.MaxTradeUSD = sizingLimits(cfg).MaxTradeUSD
+	if cfg.Trading.MinSpreadBps != 0 {
+		spreadConfig.MinSpreadBps = cfg.Trading.MinSpreadBps
+	}
+	if cfg.Trading.MinSpreadProfitUSD > 0 {
+		spreadConfig.MinProfitUSD = cfg.Trading.MinSpreadProfitUSD
+	}
+	return spreadConfig
+}
+
+// reconfigureScanner returns an applier switching the scanned tokens, route
+// search, sizing limits, slippage tolerance and spread thresholds together.
+// spreadScanner may be nil.
+func reconfigureScanner(routeFinder *arbitrage.RouteFinder, sizer *arbitrage.Sizer, slippageGuard *arbitrage.SlippageGuard, spreadScanner *arbitrage.SpreadScanner) config.Applier {
+	return func(old, new *config.Config) (func(), error) {
+		tokens, err := blockchain.FindTokens(new.Trading.Tokens)
+		if err != nil {
+			return nil, fmt.Errorf("trading.tokens: %w", err)
+		}
+		routes, limits, slippage, spread := routeConfig(new), sizingLimits(new), slippageConfig(new), spreadConfig(new)
+
+		return func() {
+			routeFinder.Reconfigure(tokens, routes)
+			sizer.SetLimits(limits)
+			slippageGuard.SetConfig(slippage)
+			if spreadScanner != nil {
+				spreadScanner.Reconfigure(tokens, spread)
+			}
+		}, nil
+	}
+}
+
+// reconfigureVenues returns an applier rebuilding the venue registry when
+// the enabled list changes. CEX venues feed streams and price sources set
+// up at startup, so adding or removing one is rejected.
+func reconfigureVenues(venues *venue.Registry, deps venue.Deps) config.Applier {
+	return func(old, new *config.Config) (func(), error) {
+		if slices.Equal(old.Venues.Enabled, new.Venues.Enabled) {
+			return nil, nil
+		}
+
+		next, err := venue.NewRegistryFromConfig(venue.ParseEnabled(strings.Join(new.Venues.Enabled, ",")), deps)
+		if err != nil {
+			return nil, fmt.Errorf("venues.enabled: %w", err)
+		}
+		if !slices.Equal(venueIDs(venues.ByKind(venue.KindCEX)), venueIDs(next.ByKind(venue.KindCEX))) {
+			return nil, errors.New("venues.enabled: enabling or disabling CEX venues requires a restart")
+		}
+
+		return func() {
+			venues.Replace(next)
+			for _, v := range venues.List() {
+				log.Printf("Venue enabled: %s (%s)", v.Name(), v.Kind())
+			}
+		}, nil
+	}
+}
+
+// venueIDs returns the sorted IDs of venues
+func venueIDs(venues []venue.Venue) []string {
+	ids := make([]string, len(venues))
+	for i, v := range venues {
+		ids[i] = v.ID()
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -0,0 +1,83 @@
+package coingecko
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a cached response body and its expiry
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// ttlCache is an in-memory response cache keyed by request URL
+type ttlCache struct {
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newTTLCache() *ttlCache {
+	return &ttlCache{
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the cached body for key if it has not expired
+func (c *ttlCache) get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// set stores body under key for ttl, evicting expired entries
+func (c *ttlCache) set(key string, body []byte, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{body: body, expiresAt: now.Add(ttl)}
+}
+
+// rateLimiter spaces requests at least interval apart
+type rateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{interval: interval}
+}
+
+// wait blocks until the caller may send a request or ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mutex.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mutex.Unlock()
+
+	if delay := time.Until(slot); delay > 0 {
+		return sleep(ctx, delay)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package coingecko
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the public API used with demo keys
+	DefaultBaseURL = "https://api.coingecko.com/api/v3"
+
+	// DemoRequestsPerMinute is the rate limit of the demo plan
+	DemoRequestsPerMinute = 30
+
+	// DefaultCacheTTL matches how often CoinGecko refreshes prices
+	DefaultCacheTTL = 60 * time.Second
+
+	// DefaultMaxRetries is how many times a throttled or failed request is retried
+	DefaultMaxRetries = 3
+
+	// DefaultRetryBackoff is the delay before the first retry; it doubles per attempt
+	DefaultRetryBackoff = 2 * time.Second
+
+	// RequestTimeout bounds a single HTTP request
+	RequestTimeout = 10 * time.Second
+)
+
+// Config configures a CoinGecko client
+type Config struct {
+	APIKey            string
+	BaseURL           string
+	HTTPClient        *http.Client
+	CacheTTL          time.Duration
+	RequestsPerMinute int
+	MaxRetries        int
+	RetryBackoff      time.Duration
+}
+
+// DefaultConfig returns the configuration for the demo plan
+func DefaultConfig(apiKey string) Config {
+	return Config{
+		APIKey:            apiKey,
+		BaseURL:           DefaultBaseURL,
+		HTTPClient:        &http.Client{Timeout: RequestTimeout},
+		CacheTTL:          DefaultCacheTTL,
+		RequestsPerMinute: DemoRequestsPerMinute,
+		MaxRetries:        DefaultMaxRetries,
+		RetryBackoff:      DefaultRetryBackoff,
+	}
+}
+
+// Client is a rate limited, caching CoinGecko API client
+type Client struct {
+	config  Config
+	cache   *ttlCache
+	limiter *rateLimiter
+}
+
+// NewClient creates a CoinGecko client, filling unset fields from DefaultConfig
+func NewClient(config Config) *Client {
+	defaults := DefaultConfig(config.APIKey)
+	if config.BaseURL == "" {
+		config.BaseURL = defaults.BaseURL
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = defaults.HTTPClient
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+	if config.RequestsPerMinute <= 0 {
+		config.RequestsPerMinute = defaults.RequestsPerMinute
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = defaults.MaxRetries
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaults.RetryBackoff
+	}
+
+	return &Client{
+		config:  config,
+		cache:   newTTLCache(),
+		limiter: newRateLimiter(time.Minute / time.Duration(config.RequestsPerMinute)),
+	}
+}
+
+// SimplePrice returns the USD price of each coin ID
+func (c *Client) SimplePrice(ctx context.Context, coinIDs ...string) (SimplePrices, error) {
+	query := url.Values{}
+	query.Set("ids", strings.Join(coinIDs, ","))
+	query.Set("vs_currencies", "usd")
+	query.Set("include_24hr_change", "true")
+	query.Set("include_24hr_vol", "true")
+	query.Set("include_last_updated_at", "true")
+
+	var prices SimplePrices
+	if err := c.get(ctx, "/simple/price", query, &prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// TokenPrice returns the USD price of tokens by contract address on a
+// platform such as "base". Result keys are lower-cased addresses.
+func (c *Client) TokenPrice(ctx context.Context, platform string, addresses ...string) (SimplePrices, error) {
+	query := url.Values{}
+	query.Set("contract_addresses", strings.ToLower(strings.Join(addresses, ",")))
+	query.Set("vs_currencies", "usd")
+	query.Set("include_24hr_change", "true")
+	query.Set("include_24hr_vol", "true")
+	query.Set("include_last_updated_at", "true")
+
+	var prices SimplePrices
+	if err := c.get(ctx, "/simple/token_price/"+url.PathEscape(platform), query, &prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// ExchangeTickers returns an exchange's tickers, optionally filtered to coin IDs
+func (c *Client) ExchangeTickers(ctx context.Context, exchangeID string, coinIDs ...string) (*ExchangeTickers, error) {
+	query := url.Values{}
+	if len(coinIDs) > 0 {
+		query.Set("coin_ids", strings.Join(coinIDs, ","))
+	}
+
+	var tickers ExchangeTickers
+	if err := c.get(ctx, "/exchanges/"+url.PathEscape(exchangeID)+"/tickers", query, &tickers); err != nil {
+		return nil, err
+	}
+	return &tickers, nil
+}
+
+// MarketChart returns USD prices, market caps and volumes for the last days
+func (c *Client) MarketChart(ctx context.Context, coinID string, days int) (*MarketChart, error) {
+	query := url.Values{}
+	query.Set("vs_currency", "usd")
+	query.Set("days", strconv.Itoa(days))
+
+	var chart MarketChart
+	if err := c.get(ctx, "/coins/"+url.PathEscape(coinID)+"/market_chart", query, &chart); err != nil {
+		return nil, err
+	}
+	return &chart, nil
+}
+
+// get fetches path with query, serving from the cache when fresh, and
+// decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	endpoint := c.config.BaseURL + path
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+
+	body, ok := c.cache.get(endpoint)
+	if !ok {
+		var err error
+		body, err = c.fetch(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+		c.cache.set(endpoint, body, c.config.CacheTTL)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("coingecko: failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// fetch performs the request, retrying throttled and transient failures
+// with exponential backoff
+func (c *Client) fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	backoff := c.config.RetryBackoff
+
+	var lastErr error
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, backoff); err != nil {
+				return nil, err
+			}
+			backoff *= 2
+		}
+
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		body, retryAfter, err := c.do(ctx, endpoint)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		if !retryable(err) {
+			return nil, err
+		}
+		if retryAfter > backoff {
+			backoff = retryAfter
+		}
+	}
+
+	return nil, fmt.Errorf("coingecko: giving up after %d attempts: %w", c.config.MaxRetries+1, lastErr)
+}
+
+// do performs a single request, returning the body or an error together with
+// any Retry-After delay the server asked for
+func (c *Client) do(ctx context.Context, endpoint string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("coingecko: failed to create request: %w", err)
+	}
+
+	req.Header.Set("accept", "application/json")
+	if c.config.APIKey != "" {
+		req.Header.Set("x-cg-demo-api-key", c.config.APIKey)
+	}
+
+	res, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("coingecko: request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("coingecko: failed to read response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, parseRetryAfter(res.Header.Get("Retry-After")), &APIError{
+			StatusCode: res.StatusCode,
+			Message:    errorMessage(body, res.Status),
+		}
+	}
+
+	return body, 0, nil
+}
+
+// retryable reports whether a failed request should be attempted again
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	// Network errors are transient
+	return true
+}
+
+// errorMessage extracts CoinGecko's error message from a response body
+func errorMessage(body []byte, fallback string) string {
+	var parsed errorResponse
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		if parsed.Status.ErrorMessage != "" {
+			return parsed.Status.ErrorMessage
+		}
+		if parsed.Error != "" {
+			return parsed.Error
+		}
+	}
+	return fallback
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
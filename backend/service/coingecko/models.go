@@ -0,0 +1,100 @@
+package coingecko
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SimplePrice is a coin's USD price from the simple/price endpoint
+type SimplePrice struct {
+	USD           float64 `json:"usd"`
+	USD24hChange  float64 `json:"usd_24h_change"`
+	USD24hVolume  float64 `json:"usd_24h_vol"`
+	LastUpdatedAt int64   `json:"last_updated_at"`
+}
+
+// UpdatedAt returns the time CoinGecko last updated the price
+func (p SimplePrice) UpdatedAt() time.Time {
+	return time.Unix(p.LastUpdatedAt, 0)
+}
+
+// SimplePrices maps coin IDs (or contract addresses) to their prices
+type SimplePrices map[string]SimplePrice
+
+// Market identifies the exchange a ticker trades on
+type Market struct {
+	Name                string `json:"name"`
+	Identifier          string `json:"identifier"`
+	HasTradingIncentive bool   `json:"has_trading_incentive"`
+}
+
+// Ticker is a single market on an exchange
+type Ticker struct {
+	Base                   string             `json:"base"`
+	Target                 string             `json:"target"`
+	Market                 Market             `json:"market"`
+	Last                   float64            `json:"last"`
+	Volume                 float64            `json:"volume"`
+	ConvertedLast          map[string]float64 `json:"converted_last"`
+	ConvertedVolume        map[string]float64 `json:"converted_volume"`
+	BidAskSpreadPercentage float64            `json:"bid_ask_spread_percentage"`
+	Timestamp              time.Time          `json:"timestamp"`
+	LastTradedAt           time.Time          `json:"last_traded_at"`
+	LastFetchAt            time.Time          `json:"last_fetch_at"`
+	IsAnomaly              bool               `json:"is_anomaly"`
+	IsStale                bool               `json:"is_stale"`
+	TradeURL               string             `json:"trade_url"`
+	CoinID                 string             `json:"coin_id"`
+	TargetCoinID           string             `json:"target_coin_id"`
+}
+
+// ExchangeTickers is the response of the exchanges/{id}/tickers endpoint
+type ExchangeTickers struct {
+	Name    string   `json:"name"`
+	Tickers []Ticker `json:"tickers"`
+}
+
+// ChartPoint is a single timestamped value in a market chart
+type ChartPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// UnmarshalJSON decodes the [milliseconds, value] pairs CoinGecko returns
+func (p *ChartPoint) UnmarshalJSON(data []byte) error {
+	var raw [2]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid chart point: %w", err)
+	}
+	p.Time = time.UnixMilli(int64(raw[0]))
+	p.Value = raw[1]
+	return nil
+}
+
+// MarketChart is the response of the coins/{id}/market_chart endpoint
+type MarketChart struct {
+	Prices       []ChartPoint `json:"prices"`
+	MarketCaps   []ChartPoint `json:"market_caps"`
+	TotalVolumes []ChartPoint `json:"total_volumes"`
+}
+
+// errorResponse is the body CoinGecko returns alongside error statuses
+type errorResponse struct {
+	Error  string `json:"error"`
+	Status struct {
+		ErrorCode    int    `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"status"`
+}
+
+// APIError is returned for non-2xx responses
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("coingecko: status %d: %s", e.StatusCode, e.Message)
+}
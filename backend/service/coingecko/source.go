@@ -0,0 +1,68 @@
+package coingecko
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arbie-buckets/blockchain"
+	"github.com/arbie-buckets/price"
+)
+
+// BasePlatform is CoinGecko's asset platform ID for Base
+const BasePlatform = "base"
+
+// DefaultCoinIDs maps token symbols to CoinGecko coin IDs. WETH is priced as
+// ETH; tokens not listed are priced by contract address on Base.
+var DefaultCoinIDs = map[string]string{
+	"ETH":  "ethereum",
+	"WETH": "ethereum",
+	"USDC": "usd-coin",
+	"DAI":  "dai",
+}
+
+var _ price.PriceSource = (*Client)(nil)
+
+// Name returns the source name
+func (c *Client) Name() string { return "coingecko" }
+
+// Price implements price.PriceSource, looking tokens up by coin ID when known
+// and by Base contract address otherwise
+func (c *Client) Price(ctx context.Context, token blockchain.TokenInfo) (*price.Price, error) {
+	var (
+		quote SimplePrice
+		found bool
+	)
+
+	if coinID, ok := DefaultCoinIDs[strings.ToUpper(token.Symbol)]; ok {
+		prices, err := c.SimplePrice(ctx, coinID)
+		if err != nil {
+			return nil, err
+		}
+		quote, found = prices[coinID]
+	} else {
+		address := strings.ToLower(token.Address)
+		prices, err := c.TokenPrice(ctx, BasePlatform, address)
+		if err != nil {
+			return nil, err
+		}
+		quote, found = prices[address]
+	}
+
+	if !found || quote.USD <= 0 {
+		return nil, fmt.Errorf("coingecko has no price for %s: %w", token.Symbol, price.ErrUnsupportedToken)
+	}
+
+	timestamp := quote.UpdatedAt()
+	if quote.LastUpdatedAt == 0 {
+		timestamp = time.Now()
+	}
+
+	return &price.Price{
+		Token:     token,
+		USD:       quote.USD,
+		Source:    c.Name(),
+		Timestamp: timestamp,
+	}, nil
+}
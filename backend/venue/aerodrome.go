@@ -0,0 +1,169 @@
+package venue
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// aerodromeABI covers the pool factory and pool methods used for quoting
+const aerodromeABI = `[
+    {"inputs": [{"name": "tokenA", "type": "address"}, {"name": "tokenB", "type": "address"}, {"name": "stable", "type": "bool"}], "name": "getPool", "outputs": [{"name": "", "type": "address"}], "stateMutability": "view", "type": "function"},
+    {"inputs": [], "name": "allPoolsLength", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+    {"inputs": [{"name": "amountIn", "type": "uint256"}, {"name": "tokenIn", "type": "address"}], "name": "getAmountOut", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+    {"inputs": [], "name": "getReserves", "outputs": [{"name": "_reserve0", "type": "uint256"}, {"name": "_reserve1", "type": "uint256"}, {"name": "_blockTimestampLast", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+    {"inputs": [], "name": "token0", "outputs": [{"name": "", "type": "address"}], "stateMutability": "view", "type": "function"}
+]`
+
+var parsedAerodromeABI = mustParseABI(aerodromeABI)
+
+// Aerodrome is a DEX venue for Aerodrome (Velodrome V2) pools on Base. Pools
+// quote their own output, so both volatile and stable curves are priced
+// exactly by the pool contract.
+type Aerodrome struct {
+	factory common.Address
+	stable  bool
+	feeBps  uint32
+	caller  ethereum.ContractCaller
+
+	pools *poolCache
+}
+
+// NewAerodrome creates a venue for either the volatile or stable Aerodrome pools
+func NewAerodrome(factory common.Address, stable bool, feeBps uint32, caller ethereum.ContractCaller) *Aerodrome {
+	return &Aerodrome{
+		factory: factory,
+		stable:  stable,
+		feeBps:  feeBps,
+		caller:  caller,
+		pools:   newPoolCache(),
+	}
+}
+
+// ID returns the venue identifier
+func (a *Aerodrome) ID() string {
+	if a.stable {
+		return "aerodrome-stable"
+	}
+	return "aerodrome"
+}
+
+// Name returns the venue display name
+func (a *Aerodrome) Name() string {
+	if a.stable {
+		return "Aerodrome Stable"
+	}
+	return "Aerodrome"
+}
+
+// Kind returns KindDEX
+func (a *Aerodrome) Kind() Kind { return KindDEX }
+
+// SupportsPair reports whether the pair has distinct token addresses and has
+// not previously been found to have no pool
+func (a *Aerodrome) SupportsPair(pair Pair) bool {
+	if !validDEXPair(pair) {
+		return false
+	}
+	pool, known := a.pools.get(pair)
+	return !known || pool != (common.Address{})
+}
+
+// Ping checks that the pool factory is reachable
+func (a *Aerodrome) Ping(ctx context.Context) error {
+	_, err := callContract(ctx, a.caller, parsedAerodromeABI, a.factory, "allPoolsLength")
+	return err
+}
+
+// Quote asks the pool for the exact output of the swap
+func (a *Aerodrome) Quote(ctx context.Context, pair Pair, amountIn *big.Int) (*Quote, error) {
+	pool, err := a.pool(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := callContract(ctx, a.caller, parsedAerodromeABI, pool, "getAmountOut", amountIn, pair.Base.HexAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quote{
+		VenueID:   a.ID(),
+		Pair:      pair,
+		AmountIn:  new(big.Int).Set(amountIn),
+		AmountOut: out[0].(*big.Int),
+		FeeBps:    a.feeBps,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// SimulateQuote prices a swap against a reserve snapshot. Only volatile
+// pools follow the constant product curve; stable pools are always quoted
+// by the pool contract, so they return nil here.
+func (a *Aerodrome) SimulateQuote(liquidity *Liquidity, amountIn *big.Int) *big.Int {
+	if a.stable {
+		return nil
+	}
+	return ConstantProductAmountOut(amountIn, liquidity.BaseReserve, liquidity.QuoteReserve, a.feeBps)
+}
+
+// Liquidity returns the pool reserves ordered as base and quote
+func (a *Aerodrome) Liquidity(ctx context.Context, pair Pair) (*Liquidity, error) {
+	pool, err := a.pool(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := callContract(ctx, a.caller, parsedAerodromeABI, pool, "getReserves")
+	if err != nil {
+		return nil, err
+	}
+	reserve0, reserve1 := out[0].(*big.Int), out[1].(*big.Int)
+
+	token0, err := a.pools.token0(ctx, a.caller, parsedAerodromeABI, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	base, quote := reserve0, reserve1
+	if token0 != pair.Base.HexAddress() {
+		base, quote = reserve1, reserve0
+	}
+
+	return &Liquidity{
+		VenueID:      a.ID(),
+		Pair:         pair,
+		Pool:         pool,
+		BaseReserve:  base,
+		QuoteReserve: quote,
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// pool resolves the pool address through the factory, caching the result
+func (a *Aerodrome) pool(ctx context.Context, pair Pair) (common.Address, error) {
+	if !validDEXPair(pair) {
+		return common.Address{}, ErrUnsupportedPair
+	}
+	if pool, known := a.pools.get(pair); known {
+		if pool == (common.Address{}) {
+			return pool, ErrUnsupportedPair
+		}
+		return pool, nil
+	}
+
+	out, err := callContract(ctx, a.caller, parsedAerodromeABI, a.factory, "getPool", pair.Base.HexAddress(), pair.Quote.HexAddress(), a.stable)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	pool := out[0].(common.Address)
+	a.pools.set(pair, pool)
+	if pool == (common.Address{}) {
+		return pool, ErrUnsupportedPair
+	}
+	return pool, nil
+}
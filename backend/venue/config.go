@@ -0,0 +1,150 @@
+package venue
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+// Deps carries the shared clients venue implementations are built from
+type Deps struct {
+	// Caller executes read-only calls against the chain; nil when the
+	// blockchain service is unavailable
+	Caller ethereum.ContractCaller
+
+	// HTTPClient is used by venues with REST APIs
+	HTTPClient *http.Client
+
+	// Chain is the chain DEX venues trade on; its contracts locate their
+	// deployments
+	Chain blockchain.ChainInfo
+}
+
+// ErrNotDeployed is returned by builders of venues with no deployment on
+// the configured chain
+var ErrNotDeployed = errors.New("venue not deployed on chain")
+
+// Builder constructs a venue from shared dependencies
+type Builder func(deps Deps) (Venue, error)
+
+// builders maps venue IDs to their constructors
+var builders = map[string]Builder{
+	"uniswap-v2": func(deps Deps) (Venue, error) {
+		factory, err := dexFactory(deps, "uniswap-v2")
+		if err != nil {
+			return nil, err
+		}
+		return NewUniswapV2("uniswap-v2", "Uniswap", factory, 30, deps.Caller), nil
+	},
+	"sushiswap": func(deps Deps) (Venue, error) {
+		factory, err := dexFactory(deps, "sushiswap")
+		if err != nil {
+			return nil, err
+		}
+		return NewUniswapV2("sushiswap", "Sushiswap", factory, 30, deps.Caller), nil
+	},
+	"alienbase": func(deps Deps) (Venue, error) {
+		factory, err := dexFactory(deps, "alienbase")
+		if err != nil {
+			return nil, err
+		}
+		return NewUniswapV2("alienbase", "Alienbase", factory, 30, deps.Caller), nil
+	},
+	"aerodrome": func(deps Deps) (Venue, error) {
+		factory, err := dexFactory(deps, "aerodrome")
+		if err != nil {
+			return nil, err
+		}
+		return NewAerodrome(factory, false, 30, deps.Caller), nil
+	},
+	"aerodrome-stable": func(deps Deps) (Venue, error) {
+		factory, err := dexFactory(deps, "aerodrome")
+		if err != nil {
+			return nil, err
+		}
+		return NewAerodrome(factory, true, 5, deps.Caller), nil
+	},
+}
+
+// dexFactory returns the pool factory of a DEX on the configured chain
+func dexFactory(deps Deps, dex string) (common.Address, error) {
+	factory, ok := deps.Chain.Contracts.DEXFactories[dex]
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w: no %s factory on %s", ErrNotDeployed, dex, deps.Chain.Name)
+	}
+	return factory, nil
+}
+
+// RegisterBuilder makes a venue implementation available under id. It is
+// meant to be called during startup, before registries are built.
+func RegisterBuilder(id string, build Builder) {
+	builders[id] = build
+}
+
+// DefaultEnabled lists the venues enabled when no configuration is given
+var DefaultEnabled = []string{"uniswap-v2", "sushiswap", "aerodrome", "alienbase"}
+
+// Available returns the IDs of all venues that can be enabled
+func Available() []string {
+	ids := make([]string, 0, len(builders))
+	for id := range builders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ParseEnabled splits a comma separated list of venue IDs, falling back to
+// DefaultEnabled when the list is empty
+func ParseEnabled(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return DefaultEnabled
+	}
+	return ids
+}
+
+// NewRegistryFromConfig builds a registry containing the enabled venues.
+// Unknown IDs are an error; venues not deployed on the chain, or that fail
+// to build, are logged and skipped so one misconfigured venue does not take
+// down the others.
+func NewRegistryFromConfig(enabled []string, deps Deps) (*Registry, error) {
+	registry := NewRegistry()
+
+	for _, id := range enabled {
+		build, ok := builders[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown venue %q", id)
+		}
+
+		v, err := build(deps)
+		if errors.Is(err, ErrNotDeployed) {
+			log.Printf("Skipping venue %s: %v", id, err)
+			continue
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to enable venue %s: %v", id, err)
+			continue
+		}
+
+		if err := registry.Register(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
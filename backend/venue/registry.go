@@ -0,0 +1,159 @@
+package venue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthCheckTimeout bounds how long a single venue health probe may take
+const HealthCheckTimeout = 5 * time.Second
+
+// Health is the result of probing a registered venue
+type Health struct {
+	ID        string
+	Name      string
+	Kind      Kind
+	Healthy   bool
+	Latency   time.Duration
+	Error     string
+	CheckedAt time.Time
+}
+
+// Registry holds the venues enabled for this process
+type Registry struct {
+	mutex  sync.RWMutex
+	venues map[string]Venue
+	order  []string
+}
+
+// NewRegistry creates an empty venue registry
+func NewRegistry() *Registry {
+	return &Registry{
+		venues: make(map[string]Venue),
+	}
+}
+
+// Register adds a venue to the registry
+func (r *Registry) Register(v Venue) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.venues[v.ID()]; exists {
+		return fmt.Errorf("venue %q already registered", v.ID())
+	}
+
+	r.venues[v.ID()] = v
+	r.order = append(r.order, v.ID())
+	return nil
+}
+
+// Get returns the venue registered under id
+func (r *Registry) Get(id string) (Venue, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	v, ok := r.venues[id]
+	return v, ok
+}
+
+// Replace swaps in the venues of other, so that holders of r see the new
+// set at once
+func (r *Registry) Replace(other *Registry) {
+	other.mutex.RLock()
+	venues := make(map[string]Venue, len(other.venues))
+	for id, v := range other.venues {
+		venues[id] = v
+	}
+	order := append([]string(nil), other.order...)
+	other.mutex.RUnlock()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.venues = venues
+	r.order = order
+}
+
+// List returns all registered venues in registration order
+func (r *Registry) List() []Venue {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	venues := make([]Venue, 0, len(r.order))
+	for _, id := range r.order {
+		venues = append(venues, r.venues[id])
+	}
+	return venues
+}
+
+// ByKind returns the registered venues of the given kind
+func (r *Registry) ByKind(kind Kind) []Venue {
+	var venues []Venue
+	for _, v := range r.List() {
+		if v.Kind() == kind {
+			venues = append(venues, v)
+		}
+	}
+	return venues
+}
+
+// SupportingPair returns the registered venues that can quote the pair
+func (r *Registry) SupportingPair(pair Pair) []Venue {
+	var venues []Venue
+	for _, v := range r.List() {
+		if v.SupportsPair(pair) {
+			venues = append(venues, v)
+		}
+	}
+	return venues
+}
+
+// Health probes every registered venue concurrently and reports the results
+// in registration order
+func (r *Registry) Health(ctx context.Context) []Health {
+	venues := r.List()
+	results := make([]Health, len(venues))
+
+	var wg sync.WaitGroup
+	for i, v := range venues {
+		wg.Add(1)
+		go func(i int, v Venue) {
+			defer wg.Done()
+			results[i] = checkHealth(ctx, v)
+		}(i, v)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// checkHealth probes a single venue, preferring its Ping method when available
+func checkHealth(ctx context.Context, v Venue) Health {
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	result := Health{
+		ID:   v.ID(),
+		Name: v.Name(),
+		Kind: v.Kind(),
+	}
+
+	start := time.Now()
+	var err error
+	if pinger, ok := v.(Pinger); ok {
+		err = pinger.Ping(ctx)
+	} else {
+		err = fmt.Errorf("venue does not support health checks")
+	}
+	result.Latency = time.Since(start)
+	result.CheckedAt = time.Now()
+
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Healthy = true
+	}
+
+	return result
+}
@@ -0,0 +1,266 @@
+package venue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// uniswapV2ABI covers the factory and pair methods used for quoting
+const uniswapV2ABI = `[
+    {"inputs": [{"name": "tokenA", "type": "address"}, {"name": "tokenB", "type": "address"}], "name": "getPair", "outputs": [{"name": "pair", "type": "address"}], "stateMutability": "view", "type": "function"},
+    {"inputs": [], "name": "allPairsLength", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+    {"inputs": [], "name": "getReserves", "outputs": [{"name": "reserve0", "type": "uint112"}, {"name": "reserve1", "type": "uint112"}, {"name": "blockTimestampLast", "type": "uint32"}], "stateMutability": "view", "type": "function"},
+    {"inputs": [], "name": "token0", "outputs": [{"name": "", "type": "address"}], "stateMutability": "view", "type": "function"}
+]`
+
+var parsedUniswapV2ABI = mustParseABI(uniswapV2ABI)
+
+// UniswapV2 is a DEX venue for Uniswap V2 style constant product pools. The
+// same adapter serves forks such as SushiSwap and Alienbase.
+type UniswapV2 struct {
+	id      string
+	name    string
+	factory common.Address
+	feeBps  uint32
+	caller  ethereum.ContractCaller
+
+	pools *poolCache
+}
+
+// NewUniswapV2 creates a venue backed by a Uniswap V2 style factory
+func NewUniswapV2(id, name string, factory common.Address, feeBps uint32, caller ethereum.ContractCaller) *UniswapV2 {
+	return &UniswapV2{
+		id:      id,
+		name:    name,
+		factory: factory,
+		feeBps:  feeBps,
+		caller:  caller,
+		pools:   newPoolCache(),
+	}
+}
+
+// ID returns the venue identifier
+func (u *UniswapV2) ID() string { return u.id }
+
+// Name returns the venue display name
+func (u *UniswapV2) Name() string { return u.name }
+
+// Kind returns KindDEX
+func (u *UniswapV2) Kind() Kind { return KindDEX }
+
+// SupportsPair reports whether the pair has distinct token addresses and has
+// not previously been found to have no pool
+func (u *UniswapV2) SupportsPair(pair Pair) bool {
+	if !validDEXPair(pair) {
+		return false
+	}
+	pool, known := u.pools.get(pair)
+	return !known || pool != (common.Address{})
+}
+
+// Ping checks that the factory is reachable
+func (u *UniswapV2) Ping(ctx context.Context) error {
+	_, err := callContract(ctx, u.caller, parsedUniswapV2ABI, u.factory, "allPairsLength")
+	return err
+}
+
+// Quote prices a swap using the pool reserves and the constant product formula
+func (u *UniswapV2) Quote(ctx context.Context, pair Pair, amountIn *big.Int) (*Quote, error) {
+	liquidity, err := u.Liquidity(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quote{
+		VenueID:   u.id,
+		Pair:      pair,
+		AmountIn:  new(big.Int).Set(amountIn),
+		AmountOut: ConstantProductAmountOut(amountIn, liquidity.BaseReserve, liquidity.QuoteReserve, u.feeBps),
+		FeeBps:    u.feeBps,
+		Timestamp: liquidity.Timestamp,
+	}, nil
+}
+
+// SimulateQuote prices a swap against a reserve snapshot
+func (u *UniswapV2) SimulateQuote(liquidity *Liquidity, amountIn *big.Int) *big.Int {
+	return ConstantProductAmountOut(amountIn, liquidity.BaseReserve, liquidity.QuoteReserve, u.feeBps)
+}
+
+// Liquidity returns the pool reserves ordered as base and quote
+func (u *UniswapV2) Liquidity(ctx context.Context, pair Pair) (*Liquidity, error) {
+	pool, err := u.pool(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := callContract(ctx, u.caller, parsedUniswapV2ABI, pool, "getReserves")
+	if err != nil {
+		return nil, err
+	}
+	reserve0, reserve1 := out[0].(*big.Int), out[1].(*big.Int)
+
+	token0, err := u.pools.token0(ctx, u.caller, parsedUniswapV2ABI, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	base, quote := reserve0, reserve1
+	if token0 != pair.Base.HexAddress() {
+		base, quote = reserve1, reserve0
+	}
+
+	return &Liquidity{
+		VenueID:      u.id,
+		Pair:         pair,
+		Pool:         pool,
+		BaseReserve:  base,
+		QuoteReserve: quote,
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// pool resolves the pair address through the factory, caching the result
+func (u *UniswapV2) pool(ctx context.Context, pair Pair) (common.Address, error) {
+	if !validDEXPair(pair) {
+		return common.Address{}, ErrUnsupportedPair
+	}
+	if pool, known := u.pools.get(pair); known {
+		if pool == (common.Address{}) {
+			return pool, ErrUnsupportedPair
+		}
+		return pool, nil
+	}
+
+	out, err := callContract(ctx, u.caller, parsedUniswapV2ABI, u.factory, "getPair", pair.Base.HexAddress(), pair.Quote.HexAddress())
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	pool := out[0].(common.Address)
+	u.pools.set(pair, pool)
+	if pool == (common.Address{}) {
+		return pool, ErrUnsupportedPair
+	}
+	return pool, nil
+}
+
+// ConstantProductAmountOut returns the output of an x*y=k swap after the fee
+func ConstantProductAmountOut(amountIn, reserveIn, reserveOut *big.Int, feeBps uint32) *big.Int {
+	if amountIn.Sign() <= 0 || reserveIn.Sign() <= 0 || reserveOut.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(int64(10000-feeBps)))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Mul(reserveIn, big.NewInt(10000))
+	denominator.Add(denominator, amountInWithFee)
+
+	return numerator.Div(numerator, denominator)
+}
+
+// validDEXPair reports whether both sides of the pair have distinct addresses
+func validDEXPair(pair Pair) bool {
+	if pair.Base.Address == "" || pair.Quote.Address == "" {
+		return false
+	}
+	return !strings.EqualFold(pair.Base.Address, pair.Quote.Address)
+}
+
+// callContract packs, executes and unpacks a read-only contract call
+func callContract(ctx context.Context, caller ethereum.ContractCaller, parsed abi.ABI, to common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	if caller == nil {
+		return nil, errors.New("chain client not available")
+	}
+
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s on %s: %w", method, to.Hex(), err)
+	}
+
+	out, err := parsed.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s result: %w", method, err)
+	}
+	return out, nil
+}
+
+// mustParseABI parses a static ABI definition, panicking on malformed input
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI definition: %v", err))
+	}
+	return parsed
+}
+
+// poolCache remembers pool addresses and token ordering per pair. A zero
+// address records that the factory has no pool for the pair.
+type poolCache struct {
+	mutex  sync.RWMutex
+	pools  map[string]common.Address
+	tokens map[common.Address]common.Address
+}
+
+func newPoolCache() *poolCache {
+	return &poolCache{
+		pools:  make(map[string]common.Address),
+		tokens: make(map[common.Address]common.Address),
+	}
+}
+
+// pairKey orders the token addresses so both directions share a cache entry
+func pairKey(pair Pair) string {
+	a, b := strings.ToLower(pair.Base.Address), strings.ToLower(pair.Quote.Address)
+	if a > b {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
+
+func (c *poolCache) get(pair Pair) (common.Address, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	pool, ok := c.pools[pairKey(pair)]
+	return pool, ok
+}
+
+func (c *poolCache) set(pair Pair, pool common.Address) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pools[pairKey(pair)] = pool
+}
+
+// token0 returns the pool's first token, querying it once per pool
+func (c *poolCache) token0(ctx context.Context, caller ethereum.ContractCaller, parsed abi.ABI, pool common.Address) (common.Address, error) {
+	c.mutex.RLock()
+	token, ok := c.tokens[pool]
+	c.mutex.RUnlock()
+	if ok {
+		return token, nil
+	}
+
+	out, err := callContract(ctx, caller, parsed, pool, "token0")
+	if err != nil {
+		return common.Address{}, err
+	}
+	token = out[0].(common.Address)
+
+	c.mutex.Lock()
+	c.tokens[pool] = token
+	c.mutex.Unlock()
+	return token, nil
+}
@@ -0,0 +1,111 @@
+package venue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/arbie-buckets/blockchain"
+)
+
+// Kind identifies whether a venue settles on-chain or on a centralized exchange
+type Kind string
+
+const (
+	KindDEX Kind = "dex"
+	KindCEX Kind = "cex"
+)
+
+// ErrUnsupportedPair is returned when a venue cannot trade the requested pair
+var ErrUnsupportedPair = errors.New("pair not supported by venue")
+
+// Pair is a directed trading pair: Base is sold for Quote
+type Pair struct {
+	Base  blockchain.TokenInfo
+	Quote blockchain.TokenInfo
+}
+
+// Reverse returns the pair with base and quote swapped
+func (p Pair) Reverse() Pair {
+	return Pair{Base: p.Quote, Quote: p.Base}
+}
+
+// String returns the pair as BASE/QUOTE
+func (p Pair) String() string {
+	return fmt.Sprintf("%s/%s", p.Base.Symbol, p.Quote.Symbol)
+}
+
+// Quote is the result of pricing a swap on a venue
+type Quote struct {
+	VenueID   string
+	Pair      Pair
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	FeeBps    uint32
+	Timestamp time.Time
+
+	// BlockNumber is the chain head the quote was taken at, or zero when the
+	// venue does not settle on-chain
+	BlockNumber uint64
+}
+
+// Liquidity describes the depth available for a pair on a venue. For DEX
+// venues the reserves are the pool balances; for CEX venues they are the
+// summed order book depth on each side.
+type Liquidity struct {
+	VenueID      string
+	Pair         Pair
+	Pool         common.Address
+	BaseReserve  *big.Int
+	QuoteReserve *big.Int
+	Timestamp    time.Time
+}
+
+// Venue is a place where a pair can be priced and traded
+type Venue interface {
+	// ID returns the stable identifier used in configuration and the API
+	ID() string
+
+	// Name returns a human readable name
+	Name() string
+
+	// Kind reports whether the venue is a DEX or a CEX
+	Kind() Kind
+
+	// SupportsPair reports whether the venue can quote the pair
+	SupportsPair(pair Pair) bool
+
+	// Quote prices selling amountIn of pair.Base for pair.Quote
+	Quote(ctx context.Context, pair Pair, amountIn *big.Int) (*Quote, error)
+
+	// Liquidity returns the current depth available for the pair
+	Liquidity(ctx context.Context, pair Pair) (*Liquidity, error)
+}
+
+// Pinger is implemented by venues that can report their own health cheaply
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Simulator is implemented by venues whose swap output can be computed
+// offline from a liquidity snapshot, avoiding a round trip per quote.
+// SimulateQuote returns nil when the snapshot cannot be used.
+type Simulator interface {
+	SimulateQuote(liquidity *Liquidity, amountIn *big.Int) *big.Int
+}
+
+// Reverse returns the liquidity snapshot viewed from the opposite direction
+func (l *Liquidity) Reverse() *Liquidity {
+	return &Liquidity{
+		VenueID:      l.VenueID,
+		Pair:         l.Pair.Reverse(),
+		Pool:         l.Pool,
+		BaseReserve:  l.QuoteReserve,
+		QuoteReserve: l.BaseReserve,
+		Timestamp:    l.Timestamp,
+	}
+}
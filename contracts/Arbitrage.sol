@@ -58,11 +58,15 @@ contract Arbitrage is Ownable {
         bool isActive
     );
     
-    constructor() Ownable(msg.sender) {
-        // Add some default exchanges
-        supportedExchanges.push(address(0x1111111111111111111111111111111111111111)); // Uniswap router
-        supportedExchanges.push(address(0x2222222222222222222222222222222222222222)); // SushiSwap router
-        supportedExchanges.push(address(0x3333333333333333333333333333333333333333)); // Aerodrome router
+    /**
+     * @dev Deploy with the router addresses of the venues enabled off-chain
+     * @param routers Exchange router addresses
+     */
+    constructor(address[] memory routers) Ownable(msg.sender) {
+        for (uint i = 0; i < routers.length; i++) {
+            require(routers[i] != address(0), "Invalid router address");
+            supportedExchanges.push(routers[i]);
+        }
     }
     
     /**
@@ -16,10 +16,17 @@ async function main() {
   console.log(`Deploying contracts with the account: ${deployerAddress}`);
   console.log(`Account balance: ${ethers.utils.formatEther(balanceBefore)} ETH`);
   
+  // Router addresses of the enabled venues, comma separated
+  const routers = (process.env.EXCHANGE_ROUTERS || "")
+    .split(",")
+    .map((address) => address.trim())
+    .filter((address) => address.length > 0);
+  console.log(`Supported exchange routers: ${routers.length ? routers.join(", ") : "none"}`);
+
   // Deploy the Arbitrage contract
   const ArbitrageFactory = await ethers.getContractFactory("Arbitrage");
   console.log("Deploying Arbitrage contract...");
-  const arbitrage = await ArbitrageFactory.deploy();
+  const arbitrage = await ArbitrageFactory.deploy(routers);
   await arbitrage.deployed();
   
   // Log the deployment address
@@ -40,6 +47,7 @@ async function main() {
     contract: "Arbitrage",
     address: arbitrage.address,
     deployer: deployerAddress,
+    routers,
     timestamp: new Date().toISOString(),
     transactionHash: arbitrage.deployTransaction.hash,
   };
@@ -58,7 +66,7 @@ async function main() {
   
   // Output verification command
   console.log("\nTo verify this contract on Etherscan, run:");
-  console.log(`npx hardhat verify --network ${networkName} ${arbitrage.address}`);
+  console.log(`npx hardhat run scripts/verify.js --network ${networkName}`);
 }
 
 main()
@@ -21,10 +21,10 @@ async function main() {
   console.log(`Verifying contract at address: ${contractAddress}`);
   
   try {
-    // The Arbitrage contract doesn't have constructor arguments
+    // The Arbitrage contract takes the router list it was deployed with
     await run("verify:verify", {
       address: contractAddress,
-      constructorArguments: [],
+      constructorArguments: [deploymentData.routers || []],
     });
     console.log("Contract verification successful!");
   } catch (error) {
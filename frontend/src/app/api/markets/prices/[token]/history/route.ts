@@ -0,0 +1,41 @@
+import { NextResponse } from 'next/server';
+
+export async function GET(
+  request: Request,
+  { params }: { params: Promise<{ token: string }> }
+) {
+  const { token } = await params;
+  const { searchParams } = new URL(request.url);
+
+  try {
+    const backendUrl = process.env.BACKEND_URL || 'http://localhost:8080';
+
+    const response = await fetch(
+      `${backendUrl}/api/markets/prices/${encodeURIComponent(token)}/history?${searchParams.toString()}`,
+      {
+        headers: {
+          'Content-Type': 'application/json',
+        },
+        next: { revalidate: 60 }, // Revalidate every minute
+      }
+    );
+
+    if (!response.ok) {
+      throw new Error(`Backend responded with status: ${response.status}`);
+    }
+
+    const data = await response.json();
+    return NextResponse.json(data);
+  } catch (error) {
+    console.error(`Error fetching ${token} price history:`, error);
+
+    return NextResponse.json(
+      {
+        token,
+        interval: searchParams.get('interval') || '1h',
+        candles: [],
+      },
+      { status: 500 }
+    );
+  }
+}
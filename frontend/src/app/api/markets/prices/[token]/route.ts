@@ -0,0 +1,38 @@
+import { NextResponse } from 'next/server';
+
+export async function GET(
+  request: Request,
+  { params }: { params: Promise<{ token: string }> }
+) {
+  const { token } = await params;
+
+  try {
+    const backendUrl = process.env.BACKEND_URL || 'http://localhost:8080';
+
+    const response = await fetch(`${backendUrl}/api/markets/prices/${encodeURIComponent(token)}`, {
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      cache: 'no-store',
+    });
+
+    if (!response.ok) {
+      throw new Error(`Backend responded with status: ${response.status}`);
+    }
+
+    const data = await response.json();
+    return NextResponse.json(data);
+  } catch (error) {
+    console.error(`Error fetching ${token} price:`, error);
+
+    return NextResponse.json(
+      {
+        token,
+        price: null,
+        confidence: 0,
+        sources: [],
+      },
+      { status: 500 }
+    );
+  }
+}
@@ -0,0 +1,43 @@
+export const dynamic = 'force-dynamic';
+
+export async function GET(request: Request) {
+  const { searchParams } = new URL(request.url);
+  const backendUrl = process.env.BACKEND_URL || 'http://localhost:8080';
+
+  // Forward Last-Event-ID so reconnecting clients resume where they left off
+  const headers: Record<string, string> = { Accept: 'text/event-stream' };
+  const lastEventId = request.headers.get('Last-Event-ID');
+  if (lastEventId) {
+    headers['Last-Event-ID'] = lastEventId;
+  }
+
+  try {
+    const response = await fetch(`${backendUrl}/api/stream?${searchParams.toString()}`, {
+      headers,
+      signal: request.signal,
+      cache: 'no-store',
+    });
+
+    if (!response.ok || !response.body) {
+      return new Response(await response.text(), {
+        status: response.status,
+        headers: { 'Content-Type': response.headers.get('Content-Type') || 'application/json' },
+      });
+    }
+
+    // Pass the event stream through unbuffered
+    return new Response(response.body, {
+      headers: {
+        'Content-Type': 'text/event-stream',
+        'Cache-Control': 'no-cache, no-transform',
+        Connection: 'keep-alive',
+      },
+    });
+  } catch (error) {
+    console.error('Error opening event stream:', error);
+    return new Response(JSON.stringify({ error: 'Event stream unavailable' }), {
+      status: 502,
+      headers: { 'Content-Type': 'application/json' },
+    });
+  }
+}